@@ -0,0 +1,28 @@
+package dto
+
+import "github.com/LMBishop/confplanner/pkg/conference"
+
+type SetAccessRequest struct {
+	Mode     string  `json:"mode" validate:"required,oneof=public restricted claim"`
+	UserIDs  []int32 `json:"userIds"`
+	GroupIDs []int32 `json:"groupIds"`
+	// a gjson path into the user's OIDC claims
+	Claim       string   `json:"claim" validate:"required_if=Mode claim"`
+	ClaimValues []string `json:"claimValues" validate:"required_if=Mode claim"`
+}
+
+type AccessResponse struct {
+	Mode        string   `json:"mode"`
+	UserIDs     []int32  `json:"userIds"`
+	GroupIDs    []int32  `json:"groupIds"`
+	Claim       string   `json:"claim,omitempty"`
+	ClaimValues []string `json:"claimValues"`
+}
+
+func (dst *AccessResponse) Scan(src conference.Access) {
+	dst.Mode = src.Mode
+	dst.UserIDs = src.UserIDs
+	dst.GroupIDs = src.GroupIDs
+	dst.Claim = src.Claim
+	dst.ClaimValues = src.ClaimValues
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+)
+
+type CreateAgendaShareRequest struct {
+	DisplayName string `json:"displayName" validate:"max=64"`
+}
+
+type AgendaShareResponse struct {
+	ConferenceID int32     `json:"conferenceID"`
+	Slug         string    `json:"slug"`
+	DisplayName  string    `json:"displayName"`
+	URL          string    `json:"url"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func (dst *AgendaShareResponse) Scan(src sqlc.AgendaShare, baseURL string) {
+	dst.ConferenceID = src.ConferenceID
+	dst.Slug = src.Slug
+	dst.DisplayName = src.DisplayName.String
+	dst.URL = baseURL + "/api/agenda/" + src.Slug
+	dst.CreatedAt = src.CreatedAt.Time
+}
+
+type SharedAgendaResponse struct {
+	DisplayName string      `json:"displayName,omitempty"`
+	Conference  interface{} `json:"conference"`
+	Events      interface{} `json:"events"`
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/LMBishop/confplanner/pkg/popularity"
 )
 
 type ConferenceResponse struct {
@@ -12,14 +13,42 @@ type ConferenceResponse struct {
 	URL   string `json:"url"`
 	Venue string `json:"venue"`
 	City  string `json:"city"`
+	// set for conferences made up entirely of custom events
+	Custom     bool       `json:"custom"`
+	Visibility string     `json:"visibility"`
+	Access     string     `json:"access"`
+	Start      string     `json:"start,omitempty"`
+	End        string     `json:"end,omitempty"`
+	ArchivedAt *time.Time `json:"archivedAt,omitempty"`
+	// minutes between fetches of the conference's schedule
+	RefreshInterval int32 `json:"refreshInterval"`
+	// the title as the conference's source has it, if Title overrides it
+	SourceTitle string `json:"sourceTitle,omitempty"`
 }
 
 func (dst *ConferenceResponse) Scan(src sqlc.Conference) {
 	dst.ID = src.ID
 	dst.Title = src.Title.String
-	dst.URL = src.Url
+	if src.TitleOverride.Valid {
+		dst.Title = src.TitleOverride.String
+		dst.SourceTitle = src.Title.String
+	}
+	dst.URL = src.Url.String
 	dst.Venue = src.Venue.String
 	dst.City = src.City.String
+	dst.Custom = !src.Url.Valid
+	dst.Visibility = src.Visibility
+	dst.Access = src.Access
+	if src.StartsOn.Valid {
+		dst.Start = src.StartsOn.Time.Format(time.DateOnly)
+	}
+	if src.EndsOn.Valid {
+		dst.End = src.EndsOn.Time.Format(time.DateOnly)
+	}
+	if src.ArchivedAt.Valid {
+		dst.ArchivedAt = &src.ArchivedAt.Time
+	}
+	dst.RefreshInterval = src.RefreshMinutes
 }
 
 type GetScheduleResponse struct {
@@ -27,10 +56,84 @@ type GetScheduleResponse struct {
 	LastUpdated time.Time   `json:"lastUpdated"`
 }
 
+type GetEventsResponse struct {
+	Events      interface{} `json:"events"`
+	NextCursor  string      `json:"nextCursor,omitempty"`
+	LastUpdated time.Time   `json:"lastUpdated"`
+}
+
 type CreateConferenceRequest struct {
 	URL string `json:"url" validate:"required"`
 }
 
-type DeleteConferenceRequest struct {
-	ID int32 `json:"id"`
+type CreateCustomConferenceRequest struct {
+	Title    string `json:"title" validate:"required"`
+	Venue    string `json:"venue"`
+	City     string `json:"city"`
+	TimeZone string `json:"timeZone" validate:"required"`
+	Start    string `json:"start" validate:"required,datetime=2006-01-02"`
+	End      string `json:"end" validate:"required,datetime=2006-01-02"`
+}
+
+// UpdateConferenceRequest changes a conference's settings. Fields left out
+// are unchanged, and an empty title removes the title override.
+type UpdateConferenceRequest struct {
+	URL             *string `json:"url" validate:"omitnil,url"`
+	RefreshInterval *int    `json:"refreshInterval" validate:"omitnil,min=1,max=1440"`
+	Title           *string `json:"title" validate:"omitnil,max=200"`
+	Visibility      *string `json:"visibility" validate:"omitnil,oneof=draft published archived"`
+}
+
+type SetVisibilityRequest struct {
+	Visibility string `json:"visibility" validate:"required,oneof=draft published archived"`
+}
+
+// DeletionImpactResponse reports how many users' favourites are lost when
+// a deleted conference is purged.
+type DeletionImpactResponse struct {
+	Users      int64 `json:"users"`
+	Favourites int64 `json:"favourites"`
+}
+
+type DeletedConferenceResponse struct {
+	ConferenceResponse
+	DeletedAt time.Time `json:"deletedAt"`
+	PurgeAt   time.Time `json:"purgeAt"`
+}
+
+func (dst *DeletedConferenceResponse) Scan(src sqlc.Conference, retention time.Duration) {
+	dst.ConferenceResponse.Scan(src)
+	dst.DeletedAt = src.DeletedAt.Time
+	dst.PurgeAt = src.DeletedAt.Time.Add(retention)
+}
+
+type DeleteConferenceResponse struct {
+	DeletedConferenceResponse
+	Impact DeletionImpactResponse `json:"impact"`
+}
+
+type GetPopularityResponse struct {
+	Threshold   int                       `json:"threshold"`
+	LastUpdated time.Time                 `json:"lastUpdated"`
+	Events      []EventPopularityResponse `json:"events"`
+}
+
+type EventPopularityResponse struct {
+	ID         int32     `json:"id"`
+	GUID       string    `json:"guid"`
+	Title      string    `json:"title"`
+	Room       string    `json:"room"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Favourites int64     `json:"favourites"`
+}
+
+func (dst *EventPopularityResponse) Scan(src popularity.EventPopularity) {
+	dst.ID = src.Event.ID
+	dst.GUID = src.Event.GUID
+	dst.Title = src.Event.Title
+	dst.Room = src.Event.Room
+	dst.Start = src.Event.Start
+	dst.End = src.Event.End
+	dst.Favourites = src.Favourites
 }
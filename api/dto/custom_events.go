@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+)
+
+// CustomEventRequest creates or replaces a custom event. Shared is ignored
+// when updating an event.
+type CustomEventRequest struct {
+	Title    string    `json:"title" validate:"required,max=200"`
+	Abstract string    `json:"abstract" validate:"max=5000"`
+	Room     string    `json:"room" validate:"max=100"`
+	URL      string    `json:"url" validate:"omitempty,url"`
+	Start    time.Time `json:"start" validate:"required"`
+	End      time.Time `json:"end" validate:"required"`
+	Shared   bool      `json:"shared"`
+}
+
+type CustomEventResponse struct {
+	ID       int32     `json:"id"`
+	GUID     string    `json:"guid"`
+	OwnerID  *int32    `json:"ownerId"`
+	Shared   bool      `json:"shared"`
+	Title    string    `json:"title"`
+	Abstract string    `json:"abstract"`
+	Room     string    `json:"room"`
+	URL      string    `json:"url"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+}
+
+func (dst *CustomEventResponse) Scan(src sqlc.CustomEvent) {
+	dst.ID = src.ID
+	dst.GUID = src.Guid.String()
+	if src.OwnerID.Valid {
+		dst.OwnerID = &src.OwnerID.Int32
+	}
+	dst.Shared = src.Shared
+	dst.Title = src.Title
+	dst.Abstract = src.Abstract
+	dst.Room = src.Room
+	dst.URL = src.Url
+	dst.Start = src.StartTime.Time
+	dst.End = src.EndTime.Time
+}
@@ -0,0 +1,9 @@
+package dto
+
+type RequestEmailVerificationRequest struct {
+	Email string `json:"email" validate:"required,email,max=254"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
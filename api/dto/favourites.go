@@ -1,6 +1,17 @@
 package dto
 
-import "github.com/LMBishop/confplanner/pkg/database/sqlc"
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/LMBishop/confplanner/pkg/favourites"
+)
 
 type CreateFavouritesRequest struct {
 	ConferenceID int32   `json:"conferenceID" validate:"required"`
@@ -13,9 +24,14 @@ type CreateFavouritesResponse struct {
 }
 
 type GetFavouritesResponse struct {
-	ID      int32   `json:"id"`
-	GUID    *string `json:"eventGuid,omitempty"`
-	EventID *int32  `json:"eventId,omitempty"`
+	ID         int32   `json:"id"`
+	GUID       *string `json:"eventGuid,omitempty"`
+	EventID    *int32  `json:"eventId,omitempty"`
+	Note       string  `json:"note"`
+	Priority   string  `json:"priority"`
+	Attendance string  `json:"attendance"`
+	EventTitle string  `json:"eventTitle,omitempty"`
+	Orphaned   bool    `json:"orphaned"`
 }
 
 func (dst *GetFavouritesResponse) Scan(src sqlc.Favourite) {
@@ -27,6 +43,11 @@ func (dst *GetFavouritesResponse) Scan(src sqlc.Favourite) {
 	if src.EventID.Valid {
 		dst.EventID = &src.EventID.Int32
 	}
+	dst.Note = src.Note.String
+	dst.Priority = src.Priority.String
+	dst.Attendance = src.Attendance.String
+	dst.EventTitle = src.EventTitle.String
+	dst.Orphaned = src.OrphanedAt.Valid
 }
 
 type DeleteFavouritesRequest struct {
@@ -34,3 +55,187 @@ type DeleteFavouritesRequest struct {
 	GUID         *string `json:"eventGuid"`
 	EventID      *int32  `json:"eventId"`
 }
+
+type UpdateFavouriteRequest struct {
+	Note       *string `json:"note" validate:"omitnil,max=2000"`
+	Priority   *string `json:"priority" validate:"omitnil,oneof='' must-see maybe"`
+	Attendance *string `json:"attendance" validate:"omitnil,oneof='' attended skipped"`
+}
+
+type FavouriteConflictResponse struct {
+	FavouriteID int32     `json:"favouriteID"`
+	GUID        string    `json:"eventGuid"`
+	EventID     int32     `json:"eventId"`
+	Title       string    `json:"title"`
+	Room        string    `json:"room"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+}
+
+func (dst *FavouriteConflictResponse) Scan(src favourites.FavouriteEvent) {
+	dst.FavouriteID = src.Favourite.ID
+	dst.GUID = src.Event.GUID
+	dst.EventID = src.Event.ID
+	dst.Title = src.Event.Title
+	dst.Room = src.Event.Room
+	dst.Start = src.Event.Start
+	dst.End = src.Event.End
+}
+
+type FavouriteReference struct {
+	GUID    *string `json:"eventGuid"`
+	EventID *int32  `json:"eventId"`
+}
+
+type BatchFavouritesRequest struct {
+	ConferenceID int32                `json:"conferenceID" validate:"required"`
+	Events       []FavouriteReference `json:"events" validate:"required,min=1,max=1000"`
+}
+
+type BatchDeleteFavouritesResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
+type FavouritesExport struct {
+	ConferenceID int32               `json:"conferenceID"`
+	ExportedAt   time.Time           `json:"exportedAt"`
+	Favourites   []ExportedFavourite `json:"favourites" validate:"max=1000,dive"`
+}
+
+type ExportedFavourite struct {
+	GUID       *string    `json:"eventGuid,omitempty"`
+	EventID    *int32     `json:"eventId,omitempty"`
+	Title      string     `json:"title,omitempty"`
+	Start      *time.Time `json:"start,omitempty"`
+	Note       string     `json:"note,omitempty" validate:"max=2000"`
+	Priority   string     `json:"priority,omitempty" validate:"omitempty,oneof=must-see maybe"`
+	Attendance string     `json:"attendance,omitempty" validate:"omitempty,oneof=attended skipped"`
+}
+
+func (dst *ExportedFavourite) Scan(src sqlc.Favourite) {
+	if src.EventGuid.Valid {
+		strGuid := src.EventGuid.String()
+		dst.GUID = &strGuid
+	}
+	if src.EventID.Valid {
+		dst.EventID = &src.EventID.Int32
+	}
+	dst.Title = src.EventTitle.String
+	if src.EventStart.Valid {
+		dst.Start = &src.EventStart.Time
+	}
+	dst.Note = src.Note.String
+	dst.Priority = src.Priority.String
+	dst.Attendance = src.Attendance.String
+}
+
+var favouritesCSVHeader = []string{"event_guid", "event_id", "title", "start", "note", "priority", "attendance"}
+
+func WriteFavouritesCSV(w io.Writer, favourites []ExportedFavourite) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(favouritesCSVHeader); err != nil {
+		return err
+	}
+
+	for _, favourite := range favourites {
+		record := make([]string, len(favouritesCSVHeader))
+		if favourite.GUID != nil {
+			record[0] = *favourite.GUID
+		}
+		if favourite.EventID != nil {
+			record[1] = strconv.Itoa(int(*favourite.EventID))
+		}
+		record[2] = favourite.Title
+		if favourite.Start != nil {
+			record[3] = favourite.Start.Format(time.RFC3339)
+		}
+		record[4] = favourite.Note
+		record[5] = favourite.Priority
+		record[6] = favourite.Attendance
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// maxImportedFavourites caps CSV imports, as the max=1000 validation does
+// for JSON ones.
+const maxImportedFavourites = 1000
+
+func ReadFavouritesCSV(r *http.Request, favourites *[]ExportedFavourite) Response {
+	reader := csv.NewReader(r.Body)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return &ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Errorf("Invalid CSV (%w)", err).Error(),
+		}
+	}
+	columns := make(map[string]int)
+	for i, column := range header {
+		columns[strings.TrimSpace(column)] = i
+	}
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return &ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Errorf("Invalid CSV (%w)", err).Error(),
+			}
+		}
+		if len(*favourites) == maxImportedFavourites {
+			return &ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("Invalid CSV (more than %d favourites)", maxImportedFavourites),
+			}
+		}
+
+		var favourite ExportedFavourite
+		if guid := field(record, "event_guid"); guid != "" {
+			favourite.GUID = &guid
+		}
+		if id := field(record, "event_id"); id != "" {
+			eventID, err := strconv.Atoi(id)
+			if err != nil {
+				return &ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Invalid CSV (bad event ID)",
+				}
+			}
+			eventID32 := int32(eventID)
+			favourite.EventID = &eventID32
+		}
+		favourite.Title = field(record, "title")
+		favourite.Note = field(record, "note")
+		favourite.Priority = field(record, "priority")
+		favourite.Attendance = field(record, "attendance")
+
+		if err := validate.Struct(&favourite); err != nil {
+			return &ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+		}
+
+		*favourites = append(*favourites, favourite)
+	}
+
+	return nil
+}
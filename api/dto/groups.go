@@ -0,0 +1,88 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/LMBishop/confplanner/pkg/group"
+)
+
+type CreateGroupRequest struct {
+	Name string `json:"name" validate:"required,max=64"`
+}
+
+type InviteGroupMemberRequest struct {
+	Username string `json:"username" validate:"required"`
+}
+
+type GroupResponse struct {
+	ID        int32     `json:"id"`
+	Name      string    `json:"name"`
+	OwnerID   int32     `json:"ownerID"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (dst *GroupResponse) Scan(src sqlc.UserGroup) {
+	dst.ID = src.ID
+	dst.Name = src.Name
+	dst.OwnerID = src.OwnerID
+	dst.CreatedAt = src.CreatedAt.Time
+}
+
+type GroupMemberResponse struct {
+	ID          int32     `json:"id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"displayName,omitempty"`
+	JoinedAt    time.Time `json:"joinedAt"`
+}
+
+func (dst *GroupMemberResponse) Scan(src sqlc.GetUserGroupMembersRow) {
+	dst.ID = src.ID
+	dst.Username = src.Username
+	dst.DisplayName = src.DisplayName.String
+	dst.JoinedAt = src.JoinedAt.Time
+}
+
+type GroupInvitationResponse struct {
+	GroupID   int32     `json:"groupID"`
+	Name      string    `json:"name"`
+	InvitedBy string    `json:"invitedBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (dst *GroupInvitationResponse) Scan(src sqlc.GetUserGroupInvitationsForUserRow) {
+	dst.GroupID = src.ID
+	dst.Name = src.Name
+	dst.InvitedBy = src.InvitedBy
+	dst.CreatedAt = src.CreatedAt.Time
+}
+
+type GroupAgendaEventResponse struct {
+	GUID    *string                     `json:"eventGuid,omitempty"`
+	EventID *int32                      `json:"eventId,omitempty"`
+	Members []GroupAgendaMemberResponse `json:"members"`
+}
+
+type GroupAgendaMemberResponse struct {
+	ID          int32  `json:"id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+func (dst *GroupAgendaEventResponse) Scan(src group.AgendaEvent) {
+	if src.EventGUID.Valid {
+		strGuid := src.EventGUID.String()
+		dst.GUID = &strGuid
+	}
+	if src.EventID.Valid {
+		dst.EventID = &src.EventID.Int32
+	}
+	dst.Members = make([]GroupAgendaMemberResponse, 0, len(src.Members))
+	for _, member := range src.Members {
+		dst.Members = append(dst.Members, GroupAgendaMemberResponse{
+			ID:          member.UserID,
+			Username:    member.Username,
+			DisplayName: member.DisplayName.String,
+		})
+	}
+}
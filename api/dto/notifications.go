@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+)
+
+type NotificationResponse struct {
+	ID           int32      `json:"id"`
+	ConferenceID *int32     `json:"conferenceID,omitempty"`
+	Kind         string     `json:"kind"`
+	Title        string     `json:"title"`
+	Body         string     `json:"body"`
+	GUID         *string    `json:"eventGuid,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	ReadAt       *time.Time `json:"readAt"`
+}
+
+func (dst *NotificationResponse) Scan(src sqlc.Notification) {
+	dst.ID = src.ID
+	if src.ConferenceID.Valid {
+		dst.ConferenceID = &src.ConferenceID.Int32
+	}
+	dst.Kind = src.Kind
+	dst.Title = src.Title
+	dst.Body = src.Body
+	if src.EventGuid.Valid {
+		strGuid := src.EventGuid.String()
+		dst.GUID = &strGuid
+	}
+	dst.CreatedAt = src.CreatedAt.Time
+	if src.ReadAt.Valid {
+		dst.ReadAt = &src.ReadAt.Time
+	}
+}
+
+type MarkAllNotificationsReadResponse struct {
+	Updated int64 `json:"updated"`
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+)
+
+// SetEventOverrideRequest replaces an event's override. Fields left out
+// are taken from the source schedule.
+type SetEventOverrideRequest struct {
+	Start     *time.Time `json:"start"`
+	End       *time.Time `json:"end"`
+	Room      *string    `json:"room" validate:"omitnil,min=1"`
+	Title     *string    `json:"title" validate:"omitnil,min=1"`
+	Cancelled bool       `json:"cancelled"`
+	Note      *string    `json:"note" validate:"omitnil,max=500"`
+}
+
+type EventOverrideResponse struct {
+	EventGUID string     `json:"eventGuid"`
+	Start     *time.Time `json:"start"`
+	End       *time.Time `json:"end"`
+	Room      *string    `json:"room"`
+	Title     *string    `json:"title"`
+	Cancelled bool       `json:"cancelled"`
+	Note      *string    `json:"note"`
+	UpdatedBy *int32     `json:"updatedBy"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+func (dst *EventOverrideResponse) Scan(src sqlc.EventOverride) {
+	dst.EventGUID = src.EventGuid.String()
+	if src.StartTime.Valid {
+		dst.Start = &src.StartTime.Time
+	}
+	if src.EndTime.Valid {
+		dst.End = &src.EndTime.Time
+	}
+	if src.Room.Valid {
+		dst.Room = &src.Room.String
+	}
+	if src.Title.Valid {
+		dst.Title = &src.Title.String
+	}
+	dst.Cancelled = src.Cancelled
+	if src.Note.Valid {
+		dst.Note = &src.Note.String
+	}
+	if src.UpdatedBy.Valid {
+		dst.UpdatedBy = &src.UpdatedBy.Int32
+	}
+	dst.UpdatedAt = src.UpdatedAt.Time
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+)
+
+type PushKeyResponse struct {
+	PublicKey string `json:"publicKey"`
+}
+
+// CreatePushSubscriptionRequest matches the JSON form of a browser's
+// PushSubscription.
+type CreatePushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint" validate:"required,url"`
+	Keys     struct {
+		P256dh string `json:"p256dh" validate:"required"`
+		Auth   string `json:"auth" validate:"required"`
+	} `json:"keys"`
+}
+
+type DeletePushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint" validate:"required"`
+}
+
+type PushSubscriptionResponse struct {
+	ID        int32     `json:"id"`
+	Endpoint  string    `json:"endpoint"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (dst *PushSubscriptionResponse) Scan(src sqlc.PushSubscription) {
+	dst.ID = src.ID
+	dst.Endpoint = src.Endpoint
+	dst.CreatedAt = src.CreatedAt.Time
+}
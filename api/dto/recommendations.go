@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/recommendation"
+)
+
+type RecommendationResponse struct {
+	GUID    string    `json:"eventGuid"`
+	EventID int32     `json:"eventId"`
+	Title   string    `json:"title"`
+	Track   string    `json:"track"`
+	Room    string    `json:"room"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Score   float64   `json:"score"`
+	Reasons []string  `json:"reasons"`
+}
+
+func (dst *RecommendationResponse) Scan(src recommendation.Recommendation) {
+	dst.GUID = src.Event.GUID
+	dst.EventID = src.Event.ID
+	dst.Title = src.Event.Title
+	dst.Track = src.Event.Track
+	dst.Room = src.Event.Room
+	dst.Start = src.Event.Start
+	dst.End = src.Event.End
+	dst.Score = src.Score
+	dst.Reasons = src.Reasons
+	if dst.Reasons == nil {
+		dst.Reasons = make([]string, 0)
+	}
+}
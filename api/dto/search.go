@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/search"
+)
+
+type SearchResultResponse struct {
+	GUID       string            `json:"eventGuid"`
+	EventID    int32             `json:"eventId"`
+	Title      string            `json:"title"`
+	Track      string            `json:"track"`
+	Room       string            `json:"room"`
+	Start      time.Time         `json:"start"`
+	End        time.Time         `json:"end"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+func (dst *SearchResultResponse) Scan(src search.Result) {
+	dst.GUID = src.Event.GUID
+	dst.EventID = src.Event.ID
+	dst.Title = src.Event.Title
+	dst.Track = src.Event.Track
+	dst.Room = src.Event.Room
+	dst.Start = src.Event.Start
+	dst.End = src.Event.End
+	dst.Score = src.Score
+	dst.Highlights = src.Highlights
+}
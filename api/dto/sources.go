@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/conference"
+)
+
+type AddSourceRequest struct {
+	URL   string `json:"url" validate:"required,url"`
+	Label string `json:"label" validate:"required,max=50"`
+}
+
+type GetSourcesResponse struct {
+	Sources   []SourceResponse        `json:"sources"`
+	Conflicts []MergeConflictResponse `json:"conflicts"`
+}
+
+type SourceResponse struct {
+	ID        int32      `json:"id"`
+	Label     string     `json:"label"`
+	URL       string     `json:"url"`
+	Events    int        `json:"events"`
+	FetchedAt *time.Time `json:"fetchedAt"`
+	Error     string     `json:"error,omitempty"`
+}
+
+func (dst *SourceResponse) Scan(src conference.Source) {
+	dst.ID = src.ID
+	dst.Label = src.Label
+	dst.URL = src.URL
+	dst.Events = src.Events
+	if !src.FetchedAt.IsZero() {
+		dst.FetchedAt = &src.FetchedAt
+	}
+	dst.Error = src.Error
+}
+
+type MergeConflictResponse struct {
+	GUID    string   `json:"guid"`
+	Title   string   `json:"title"`
+	Sources []string `json:"sources"`
+	Fields  []string `json:"fields"`
+}
+
+func (dst *MergeConflictResponse) Scan(src conference.MergeConflict) {
+	dst.GUID = src.GUID
+	dst.Title = src.Title
+	dst.Sources = src.Sources
+	dst.Fields = src.Fields
+}
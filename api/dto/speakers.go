@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+)
+
+type SpeakerResponse struct {
+	ID         int               `json:"id"`
+	GUID       string            `json:"guid,omitempty"`
+	Name       string            `json:"name"`
+	Biography  string            `json:"biography,omitempty"`
+	Avatar     string            `json:"avatar,omitempty"`
+	Links      []conference.Link `json:"links"`
+	EventCount int               `json:"eventCount"`
+}
+
+func (dst *SpeakerResponse) Scan(src conference.Speaker) {
+	dst.ID = src.Person.ID
+	dst.GUID = src.Person.GUID
+	dst.Name = src.Person.Name
+	dst.Biography = src.Person.Biography
+	dst.Avatar = src.Person.Avatar
+	dst.Links = src.Person.Links
+	if dst.Links == nil {
+		dst.Links = make([]conference.Link, 0)
+	}
+	dst.EventCount = len(src.Events)
+}
+
+type SpeakerDetailResponse struct {
+	SpeakerResponse
+	Events interface{} `json:"events"`
+}
+
+type SpeakerFollowResponse struct {
+	ConferenceID int32     `json:"conferenceID"`
+	PersonID     int32     `json:"personID"`
+	PersonName   string    `json:"personName"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func (dst *SpeakerFollowResponse) Scan(src sqlc.SpeakerFollow) {
+	dst.ConferenceID = src.ConferenceID
+	dst.PersonID = src.PersonID
+	dst.PersonName = src.PersonName
+	dst.CreatedAt = src.CreatedAt.Time
+}
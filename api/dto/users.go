@@ -1,5 +1,12 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/LMBishop/confplanner/pkg/session"
+)
+
 type RegisterRequest struct {
 	Username string `json:"username" validate:"required"`
 	Password string `json:"password" validate:"required"`
@@ -8,3 +15,167 @@ type RegisterRequest struct {
 type RegisterResponse struct {
 	ID int32 `json:"id"`
 }
+
+type DeleteUserRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password"`
+}
+
+type ExportUserResponse struct {
+	ExportedAt        time.Time                  `json:"exportedAt"`
+	User              ExportUser                 `json:"user"`
+	Profile           UserProfileResponse        `json:"profile"`
+	Favourites        []ExportFavourite          `json:"favourites"`
+	CustomEvents      []ExportCustomEvent        `json:"customEvents"`
+	Calendar          *ExportCalendar            `json:"calendar"`
+	Shares            []ExportAgendaShare        `json:"shares"`
+	Groups            []GroupResponse            `json:"groups"`
+	GroupInvitations  []GroupInvitationResponse  `json:"groupInvitations"`
+	Follows           []SpeakerFollowResponse    `json:"follows"`
+	Notifications     []NotificationResponse     `json:"notifications"`
+	PushSubscriptions []PushSubscriptionResponse `json:"pushSubscriptions"`
+	EmailDigests      []ExportEmailDigest        `json:"emailDigests"`
+	Webhooks          []ExportWebhook            `json:"webhooks"`
+	Sessions          []ExportSession            `json:"sessions"`
+}
+
+type ExportUser struct {
+	ID          int32  `json:"id"`
+	Username    string `json:"username"`
+	Admin       bool   `json:"admin"`
+	HasPassword bool   `json:"hasPassword"`
+}
+
+func (dst *ExportUser) Scan(src sqlc.User) {
+	dst.ID = src.ID
+	dst.Username = src.Username
+	dst.Admin = src.Admin
+	dst.HasPassword = src.Password.Valid
+}
+
+type ExportFavourite struct {
+	ID           int32   `json:"id"`
+	ConferenceID int32   `json:"conferenceID"`
+	GUID         *string `json:"eventGuid,omitempty"`
+	EventID      *int32  `json:"eventId,omitempty"`
+	Note         string  `json:"note,omitempty"`
+	Priority     string  `json:"priority,omitempty"`
+	Attendance   string  `json:"attendance,omitempty"`
+}
+
+func (dst *ExportFavourite) Scan(src sqlc.Favourite) {
+	dst.ID = src.ID
+	dst.ConferenceID = src.ConferenceID
+	if src.EventGuid.Valid {
+		strGuid := src.EventGuid.String()
+		dst.GUID = &strGuid
+	}
+	if src.EventID.Valid {
+		dst.EventID = &src.EventID.Int32
+	}
+	dst.Note = src.Note.String
+	dst.Priority = src.Priority.String
+	dst.Attendance = src.Attendance.String
+}
+
+type ExportCustomEvent struct {
+	ConferenceID int32 `json:"conferenceID"`
+	CustomEventResponse
+}
+
+func (dst *ExportCustomEvent) Scan(src sqlc.CustomEvent) {
+	dst.ConferenceID = src.ConferenceID
+	dst.CustomEventResponse.Scan(src)
+}
+
+type ExportEmailDigest struct {
+	ConferenceID int32     `json:"conferenceID"`
+	Day          string    `json:"day"`
+	SentAt       time.Time `json:"sentAt"`
+}
+
+func (dst *ExportEmailDigest) Scan(src sqlc.EmailDigest) {
+	dst.ConferenceID = src.ConferenceID
+	dst.Day = src.Day.Time.Format(time.DateOnly)
+	dst.SentAt = src.SentAt.Time
+}
+
+type ExportWebhook struct {
+	WebhookResponse
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+}
+
+type ExportCalendar struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+func (dst *ExportCalendar) Scan(src sqlc.Calendar) {
+	dst.ID = src.ID
+	dst.Name = src.Name
+	dst.Key = src.Key
+}
+
+type ExportSession struct {
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"userAgent"`
+	LoginTime time.Time `json:"loginTime"`
+}
+
+func (dst *ExportSession) Scan(src session.UserSession) {
+	dst.IP = src.IP
+	dst.UserAgent = src.UserAgent
+	dst.LoginTime = src.LoginTime
+}
+
+type UserProfileResponse struct {
+	DisplayName          string `json:"displayName"`
+	TimeZone             string `json:"timeZone"`
+	Clock                string `json:"clock"`
+	Language             string `json:"language"`
+	DefaultConferenceID  *int32 `json:"defaultConferenceID"`
+	FavouritesVisibility string `json:"favouritesVisibility"`
+	Email                string `json:"email"`
+	EmailDigest          bool   `json:"emailDigest"`
+	EmailAlerts          bool   `json:"emailAlerts"`
+}
+
+func (dst *UserProfileResponse) Scan(src sqlc.UserProfile) {
+	dst.DisplayName = src.DisplayName.String
+	dst.TimeZone = src.TimeZone.String
+	dst.Clock = src.Clock
+	dst.Language = src.Language.String
+	if src.DefaultConferenceID.Valid {
+		dst.DefaultConferenceID = &src.DefaultConferenceID.Int32
+	}
+	dst.FavouritesVisibility = src.FavouritesVisibility
+	dst.Email = src.Email.String
+	dst.EmailDigest = src.EmailDigest
+	dst.EmailAlerts = src.EmailAlerts
+}
+
+type UpdateUserProfileRequest struct {
+	DisplayName          *string `json:"displayName" validate:"omitnil,max=64"`
+	TimeZone             *string `json:"timeZone"`
+	Clock                *string `json:"clock" validate:"omitnil,oneof=12h 24h"`
+	Language             *string `json:"language" validate:"omitnil,eq=|bcp47_language_tag"`
+	DefaultConferenceID  *int32  `json:"defaultConferenceID"`
+	FavouritesVisibility *string `json:"favouritesVisibility" validate:"omitnil,oneof=private groups"`
+	EmailDigest          *bool   `json:"emailDigest"`
+	EmailAlerts          *bool   `json:"emailAlerts"`
+}
+
+type ExportAgendaShare struct {
+	ConferenceID int32     `json:"conferenceID"`
+	Slug         string    `json:"slug"`
+	DisplayName  string    `json:"displayName,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func (dst *ExportAgendaShare) Scan(src sqlc.AgendaShare) {
+	dst.ConferenceID = src.ConferenceID
+	dst.Slug = src.Slug
+	dst.DisplayName = src.DisplayName.String
+	dst.CreatedAt = src.CreatedAt.Time
+}
@@ -38,6 +38,12 @@ func WrapResponseFunc(dtoFunc func(http.ResponseWriter, *http.Request) error) ht
 
 func WriteDto(w http.ResponseWriter, r *http.Request, err error) {
 	if o, ok := err.(Response); ok {
+		// these responses can't have a body
+		if o.Status() == http.StatusNotModified || o.Status() == http.StatusNoContent {
+			w.WriteHeader(o.Status())
+			return
+		}
+
 		data, err := json.Marshal(o)
 		if err != nil {
 			w.WriteHeader(500)
@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/LMBishop/confplanner/pkg/webhook"
+)
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" validate:"required,url"`
+	Events []string `json:"events"`
+}
+
+type CreateUserWebhookRequest struct {
+	ConferenceID int32    `json:"conferenceID" validate:"required"`
+	URL          string   `json:"url" validate:"required,url"`
+	Events       []string `json:"events"`
+}
+
+type WebhookResponse struct {
+	ID           int32     `json:"id"`
+	ConferenceID int32     `json:"conferenceID"`
+	URL          string    `json:"url"`
+	Events       []string  `json:"events"`
+	CreatedAt    time.Time `json:"createdAt"`
+	// only given when the webhook is created
+	Secret string `json:"secret,omitempty"`
+}
+
+func (dst *WebhookResponse) Scan(src sqlc.Webhook) {
+	dst.ID = src.ID
+	dst.ConferenceID = src.ConferenceID
+	dst.URL = src.Url
+	dst.Events = src.Events
+	dst.CreatedAt = src.CreatedAt.Time
+}
+
+type WebhookDeliveryResponse struct {
+	ID             int32           `json:"id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"nextAttemptAt"`
+	ResponseStatus *int32          `json:"responseStatus"`
+	ResponseBody   *string         `json:"responseBody"`
+	Error          *string         `json:"error"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	CompletedAt    *time.Time      `json:"completedAt"`
+}
+
+func (dst *WebhookDeliveryResponse) Scan(src sqlc.WebhookDelivery) {
+	dst.ID = src.ID
+	dst.Event = src.Event
+	dst.Payload = src.Payload
+	dst.Status = src.Status
+	dst.Attempts = src.Attempts
+	if src.Status == webhook.StatusPending && src.NextAttemptAt.Valid {
+		dst.NextAttemptAt = &src.NextAttemptAt.Time
+	}
+	if src.ResponseStatus.Valid {
+		dst.ResponseStatus = &src.ResponseStatus.Int32
+	}
+	if src.ResponseBody.Valid {
+		dst.ResponseBody = &src.ResponseBody.String
+	}
+	if src.Error.Valid {
+		dst.Error = &src.Error.String
+	}
+	dst.CreatedAt = src.CreatedAt.Time
+	if src.CompletedAt.Valid {
+		dst.CompletedAt = &src.CompletedAt.Time
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/LMBishop/confplanner/api/dto"
+	"github.com/LMBishop/confplanner/pkg/conference"
+)
+
+func GetConferenceAccess(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		access, err := service.GetAccess(int32(conferenceID))
+		if err != nil {
+			return accessError(err)
+		}
+
+		var response dto.AccessResponse
+		response.Scan(*access)
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func SetConferenceAccess(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.SetAccessRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		access, err := service.SetAccess(int32(conferenceID), conference.Access{
+			Mode:        request.Mode,
+			UserIDs:     request.UserIDs,
+			GroupIDs:    request.GroupIDs,
+			Claim:       request.Claim,
+			ClaimValues: request.ClaimValues,
+		})
+		if err != nil {
+			return accessError(err)
+		}
+
+		var response dto.AccessResponse
+		response.Scan(*access)
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func accessError(err error) error {
+	if errors.Is(err, conference.ErrConferenceNotFound) {
+		return &dto.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Conference not found",
+		}
+	} else if errors.Is(err, conference.ErrInvalidAccess) {
+		return &dto.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+	}
+	return err
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/LMBishop/confplanner/api/dto"
+	"github.com/LMBishop/confplanner/pkg/agenda"
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/session"
+	"github.com/golang-cz/nilslice"
+)
+
+func GetAgendaShare(service agenda.Service, baseURL string) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		share, err := service.GetShareForUser(session.UserID, int32(conferenceID))
+		if err != nil {
+			if errors.Is(err, agenda.ErrShareNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Agenda is not shared",
+				}
+			}
+			return err
+		}
+
+		var response dto.AgendaShareResponse
+		response.Scan(*share, baseURL)
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func CreateAgendaShare(service agenda.Service, baseURL string) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		var request dto.CreateAgendaShareRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		share, err := service.CreateShareForUser(session.UserID, int32(conferenceID), request.DisplayName)
+		if err != nil {
+			if errors.Is(err, agenda.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			} else if errors.Is(err, agenda.ErrNotShareable) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: err.Error(),
+				}
+			}
+			return err
+		}
+
+		var response dto.AgendaShareResponse
+		response.Scan(*share, baseURL)
+		return &dto.OkResponse{
+			Code: http.StatusCreated,
+			Data: response,
+		}
+	})
+}
+
+func DeleteAgendaShare(service agenda.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		err = service.DeleteShareForUser(session.UserID, int32(conferenceID))
+		if err != nil {
+			if errors.Is(err, agenda.ErrShareNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Agenda is not shared",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+		}
+	})
+}
+
+func GetSharedAgenda(service agenda.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		sharedAgenda, err := service.GetSharedAgenda(r.PathValue("slug"))
+		if err != nil {
+			if errors.Is(err, agenda.ErrShareNotFound) || errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Agenda not found",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: &dto.SharedAgendaResponse{
+				DisplayName: sharedAgenda.Share.DisplayName.String,
+				Conference:  sharedAgenda.Conference,
+				Events:      nilslice.Initialize(sharedAgenda.Events),
+			},
+		}
+	})
+}
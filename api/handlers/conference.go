@@ -2,11 +2,15 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/LMBishop/confplanner/api/dto"
 	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/popularity"
+	"github.com/LMBishop/confplanner/pkg/session"
 	"github.com/golang-cz/nilslice"
 )
 
@@ -20,11 +24,27 @@ func GetSchedule(service conference.Service) http.HandlerFunc {
 			}
 		}
 
-		schedule, lastUpdated, err := service.GetSchedule(int32(conferenceID))
+		session := r.Context().Value("session").(*session.UserSession)
+
+		// the schedule includes the user's own personal events
+		if err := checkConferenceAccess(service, int32(conferenceID), session); err != nil {
+			return err
+		}
+
+		schedule, lastUpdated, err := service.GetScheduleForUser(int32(conferenceID), session.UserID)
 		if err != nil {
 			return err
 		}
 
+		// the response includes when the schedule was last fetched as
+		// well as its contents
+		etag := fmt.Sprintf(`W/"%.16s-%d"`, schedule.Checksum, lastUpdated.Unix())
+		if notModified(w, r, etag, lastUpdated) {
+			return &dto.OkResponse{
+				Code: http.StatusNotModified,
+			}
+		}
+
 		return &dto.OkResponse{
 			Code: http.StatusOK,
 			Data: &dto.GetScheduleResponse{
@@ -35,9 +55,122 @@ func GetSchedule(service conference.Service) http.HandlerFunc {
 	})
 }
 
+func GetEvents(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		query := r.URL.Query()
+		filter := conference.EventFilter{
+			Days:         query["day"],
+			Rooms:        query["room"],
+			Tracks:       query["track"],
+			Types:        query["type"],
+			Speaker:      query.Get("speaker"),
+			HappeningNow: query.Get("now") == "true",
+			UserID:       session.UserID,
+		}
+		if query.Has("from") {
+			filter.From, err = time.Parse(time.RFC3339, query.Get("from"))
+			if err != nil {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Bad from time",
+				}
+			}
+		}
+		if query.Has("to") {
+			filter.To, err = time.Parse(time.RFC3339, query.Get("to"))
+			if err != nil {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Bad to time",
+				}
+			}
+		}
+		if query.Has("startingWithin") {
+			minutes, err := strconv.Atoi(query.Get("startingWithin"))
+			if err != nil || minutes < 1 {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Bad startingWithin",
+				}
+			}
+			filter.StartingWithin = time.Duration(minutes) * time.Minute
+		}
+
+		limit := 100
+		if query.Has("limit") {
+			limit, err = strconv.Atoi(query.Get("limit"))
+			if err != nil || limit < 1 || limit > 500 {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Bad limit",
+				}
+			}
+		}
+
+		if err := checkConferenceAccess(service, int32(conferenceID), session); err != nil {
+			return err
+		}
+
+		page, err := service.GetEvents(int32(conferenceID), filter, query.Get("cursor"), limit)
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			} else if errors.Is(err, conference.ErrInvalidCursor) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Bad cursor",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: &dto.GetEventsResponse{
+				Events:      nilslice.Initialize(page.Events),
+				NextCursor:  page.NextCursor,
+				LastUpdated: page.LastUpdated,
+			},
+		}
+	})
+}
+
 func GetConferences(service conference.Service) http.HandlerFunc {
 	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
-		conferences, err := service.GetConferences()
+		session := r.Context().Value("session").(*session.UserSession)
+
+		query := r.URL.Query()
+		filter := conference.ConferenceFilter{
+			When: query.Get("when"),
+		}
+		if filter.When != "" && filter.When != conference.WhenUpcoming && filter.When != conference.WhenOngoing && filter.When != conference.WhenPast {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad when (expected upcoming, ongoing or past)",
+			}
+		}
+		if session.Admin {
+			filter.Visibilities = query["visibility"]
+		} else {
+			// drafts are only visible to admins
+			filter.Visibilities = []string{conference.VisibilityPublished, conference.VisibilityArchived}
+			filter.UserID = session.UserID
+		}
+
+		conferences, err := service.GetConferences(filter)
 		if err != nil {
 			return err
 		}
@@ -83,21 +216,303 @@ func CreateConference(service conference.Service) http.HandlerFunc {
 	})
 }
 
-func DeleteConference(service conference.Service) http.HandlerFunc {
+func UpdateConference(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.UpdateConferenceRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		update := conference.ConferenceUpdate{
+			URL:        request.URL,
+			Title:      request.Title,
+			Visibility: request.Visibility,
+		}
+		if request.RefreshInterval != nil {
+			interval := time.Duration(*request.RefreshInterval) * time.Minute
+			update.RefreshInterval = &interval
+		}
+
+		updatedConference, err := service.UpdateConference(int32(conferenceID), update)
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			} else if errors.Is(err, conference.ErrScheduleFetch) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Could not fetch schedule from URL (is it a valid pentabarf XML file?)",
+				}
+			} else if errors.Is(err, conference.ErrInvalidRefreshInterval) || errors.Is(err, conference.ErrInvalidVisibility) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: err.Error(),
+				}
+			}
+			return err
+		}
+
+		var response dto.ConferenceResponse
+		response.Scan(*updatedConference)
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func SetConferenceVisibility(service conference.Service) http.HandlerFunc {
 	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
-		var request dto.DeleteConferenceRequest
+		var request dto.SetVisibilityRequest
 		if err := dto.ReadDto(r, &request); err != nil {
 			return err
 		}
 
-		err := service.DeleteConference(request.ID)
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		updatedConference, err := service.SetVisibility(int32(conferenceID), request.Visibility)
 		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			} else if errors.Is(err, conference.ErrInvalidVisibility) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: err.Error(),
+				}
+			} else if errors.Is(err, conference.ErrNothingToArchive) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusConflict,
+					Message: err.Error(),
+				}
+			}
 			return err
 		}
 
+		var response dto.ConferenceResponse
+		response.Scan(*updatedConference)
 		return &dto.OkResponse{
 			Code: http.StatusOK,
-			Data: nil,
+			Data: response,
 		}
 	})
 }
+
+func GetDeletionImpact(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		if _, err := service.GetConference(int32(conferenceID)); err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			}
+			return err
+		}
+
+		impact, err := service.GetDeletionImpact(int32(conferenceID))
+		if err != nil {
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: dto.DeletionImpactResponse{
+				Users:      impact.Users,
+				Favourites: impact.Favourites,
+			},
+		}
+	})
+}
+
+func DeleteConference(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		deletedConference, err := service.DeleteConference(int32(conferenceID))
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			}
+			return err
+		}
+
+		impact, err := service.GetDeletionImpact(int32(conferenceID))
+		if err != nil {
+			return err
+		}
+
+		var response dto.DeleteConferenceResponse
+		response.Scan(*deletedConference, service.Retention())
+		response.Impact = dto.DeletionImpactResponse{
+			Users:      impact.Users,
+			Favourites: impact.Favourites,
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func GetDeletedConferences(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferences, err := service.GetDeletedConferences()
+		if err != nil {
+			return err
+		}
+
+		response := make([]dto.DeletedConferenceResponse, len(conferences))
+		for i, c := range conferences {
+			response[i].Scan(c, service.Retention())
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func RestoreConference(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		restoredConference, err := service.RestoreConference(int32(conferenceID))
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Deleted conference not found (it may have been purged)",
+				}
+			}
+			return err
+		}
+
+		var response dto.ConferenceResponse
+		response.Scan(*restoredConference)
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func GetPopularity(service popularity.Service, conferenceService conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+		if err := checkConferenceAccess(conferenceService, int32(conferenceID), session); err != nil {
+			return err
+		}
+
+		events, lastUpdated, err := service.GetPopularityForConference(int32(conferenceID))
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			}
+			return err
+		}
+
+		response := &dto.GetPopularityResponse{
+			Threshold:   service.Threshold(),
+			LastUpdated: lastUpdated,
+			Events:      make([]dto.EventPopularityResponse, 0, len(events)),
+		}
+		for _, event := range events {
+			var eventResponse dto.EventPopularityResponse
+			eventResponse.Scan(event)
+			response.Events = append(response.Events, eventResponse)
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+// checkConferenceAccess returns an error response if the conference
+// doesn't exist, or the user can't access it. Conferences a user can't
+// access are reported as not found, so as not to reveal them.
+func checkConferenceAccess(service conference.Service, conferenceID int32, session *session.UserSession) error {
+	notFound := &dto.ErrorResponse{
+		Code:    http.StatusNotFound,
+		Message: "Conference not found",
+	}
+
+	if session.Admin {
+		if _, err := service.GetConference(conferenceID); err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return notFound
+			}
+			return err
+		}
+		return nil
+	}
+
+	ok, err := service.CanAccess(conferenceID, session.UserID)
+	if err != nil {
+		if errors.Is(err, conference.ErrConferenceNotFound) {
+			return notFound
+		}
+		return err
+	}
+	if !ok {
+		return notFound
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/LMBishop/confplanner/api/dto"
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/LMBishop/confplanner/pkg/session"
+)
+
+func CreateCustomConference(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.CreateCustomConferenceRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		// the validator has already checked the dates parse
+		start, _ := time.Parse(time.DateOnly, request.Start)
+		end, _ := time.Parse(time.DateOnly, request.End)
+
+		createdConference, err := service.CreateCustomConference(conference.CustomConference{
+			Title:    request.Title,
+			Venue:    request.Venue,
+			City:     request.City,
+			TimeZone: request.TimeZone,
+			Start:    start,
+			End:      end,
+		})
+		if err != nil {
+			if errors.Is(err, conference.ErrInvalidConferenceTZ) || errors.Is(err, conference.ErrInvalidConferenceEnd) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: err.Error(),
+				}
+			}
+			return err
+		}
+
+		var response dto.ConferenceResponse
+		response.Scan(*createdConference)
+		return &dto.OkResponse{
+			Code: http.StatusCreated,
+			Data: response,
+		}
+	})
+}
+
+func GetCustomEvents(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+		if err := checkConferenceAccess(service, int32(conferenceID), session); err != nil {
+			return err
+		}
+
+		events, err := service.GetCustomEvents(int32(conferenceID), session.UserID)
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			}
+			return err
+		}
+
+		response := make([]dto.CustomEventResponse, len(events))
+		for i, event := range events {
+			response[i].Scan(event)
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func CreateCustomEvent(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.CustomEventRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+		if err := checkConferenceAccess(service, int32(conferenceID), session); err != nil {
+			return err
+		}
+
+		if request.Shared && !session.Admin {
+			return &dto.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Only admins can add events to the shared schedule",
+			}
+		}
+
+		created, err := service.CreateCustomEvent(int32(conferenceID), session.UserID, customEventDetails(request))
+		if err != nil {
+			return customEventError(err)
+		}
+
+		var response dto.CustomEventResponse
+		response.Scan(*created)
+
+		return &dto.OkResponse{
+			Code: http.StatusCreated,
+			Data: response,
+		}
+	})
+}
+
+func UpdateCustomEvent(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.CustomEventRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		event, err := editableCustomEvent(service, r)
+		if err != nil {
+			return err
+		}
+
+		updated, err := service.UpdateCustomEvent(event.ConferenceID, event.ID, customEventDetails(request))
+		if err != nil {
+			return customEventError(err)
+		}
+
+		var response dto.CustomEventResponse
+		response.Scan(*updated)
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func DeleteCustomEvent(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		event, err := editableCustomEvent(service, r)
+		if err != nil {
+			return err
+		}
+
+		if err := service.DeleteCustomEvent(event.ConferenceID, event.ID); err != nil {
+			return customEventError(err)
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+		}
+	})
+}
+
+// editableCustomEvent finds the custom event a request refers to, as long
+// as the current user may change it. Personal events can only be changed by
+// their owner, and shared events by admins.
+func editableCustomEvent(service conference.Service, r *http.Request) (*sqlc.CustomEvent, error) {
+	conferenceID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return nil, &dto.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Bad conference ID",
+		}
+	}
+
+	eventID, err := strconv.Atoi(r.PathValue("eventID"))
+	if err != nil {
+		return nil, &dto.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Bad event ID",
+		}
+	}
+
+	event, err := service.GetCustomEvent(int32(conferenceID), int32(eventID))
+	if err != nil {
+		return nil, customEventError(err)
+	}
+
+	session := r.Context().Value("session").(*session.UserSession)
+
+	if event.Shared {
+		if !session.Admin {
+			return nil, &dto.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Only admins can change the shared schedule",
+			}
+		}
+	} else if event.OwnerID.Int32 != session.UserID {
+		// other users' personal events are hidden entirely
+		return nil, &dto.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Event not found",
+		}
+	}
+
+	return event, nil
+}
+
+func customEventDetails(request dto.CustomEventRequest) conference.CustomEventDetails {
+	return conference.CustomEventDetails{
+		Title:    request.Title,
+		Abstract: request.Abstract,
+		Room:     request.Room,
+		URL:      request.URL,
+		Start:    request.Start,
+		End:      request.End,
+		Shared:   request.Shared,
+	}
+}
+
+func customEventError(err error) error {
+	if errors.Is(err, conference.ErrConferenceNotFound) {
+		return &dto.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Conference not found",
+		}
+	} else if errors.Is(err, conference.ErrCustomEventNotFound) {
+		return &dto.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Event not found",
+		}
+	} else if errors.Is(err, conference.ErrInvalidCustomEvent) {
+		return &dto.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/LMBishop/confplanner/api/dto"
+	"github.com/LMBishop/confplanner/pkg/mail"
+	"github.com/LMBishop/confplanner/pkg/session"
+)
+
+func RequestEmailVerification(service mail.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.RequestEmailVerificationRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		if err := service.RequestVerification(session.UserID, request.Email); err != nil {
+			if errors.Is(err, mail.ErrMailDisabled) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusServiceUnavailable,
+					Message: "Email is not configured on this server",
+				}
+			} else if errors.Is(err, mail.ErrInvalidAddress) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Invalid email address",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusAccepted,
+		}
+	})
+}
+
+func VerifyEmail(service mail.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.VerifyEmailRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		if err := service.VerifyEmail(request.Token); err != nil {
+			if errors.Is(err, mail.ErrInvalidToken) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "This link is invalid or has expired",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+		}
+	})
+}
+
+func RemoveEmail(service mail.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		session := r.Context().Value("session").(*session.UserSession)
+
+		if err := service.RemoveEmail(session.UserID); err != nil {
+			if errors.Is(err, mail.ErrEmailNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "No email address set",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+		}
+	})
+}
@@ -1,13 +1,20 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/LMBishop/confplanner/api/dto"
+	"github.com/LMBishop/confplanner/pkg/agenda"
+	"github.com/LMBishop/confplanner/pkg/conference"
 	"github.com/LMBishop/confplanner/pkg/favourites"
 	"github.com/LMBishop/confplanner/pkg/session"
-	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func CreateFavourite(service favourites.Service) http.HandlerFunc {
@@ -17,26 +24,21 @@ func CreateFavourite(service favourites.Service) http.HandlerFunc {
 			return err
 		}
 
-		if request.GUID == nil && request.EventID == nil {
-			return &dto.ErrorResponse{
-				Code:    http.StatusBadRequest,
-				Message: "One of event GUID or event ID must be specified",
-			}
+		event, err := parseEventReference(dto.FavouriteReference{GUID: request.GUID, EventID: request.EventID})
+		if err != nil {
+			return err
 		}
 
 		session := r.Context().Value("session").(*session.UserSession)
-		var uuid pgtype.UUID
-		if request.GUID != nil {
-			if err := uuid.Scan(*request.GUID); err != nil {
+
+		createdFavourite, err := service.CreateFavouriteForUser(session.UserID, event.EventGUID, event.EventID, request.ConferenceID)
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
 				return &dto.ErrorResponse{
-					Code:    http.StatusBadRequest,
-					Message: "Bad event GUID",
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
 				}
 			}
-		}
-
-		createdFavourite, err := service.CreateFavouriteForUser(session.UserID, uuid, request.EventID, request.ConferenceID)
-		if err != nil {
 			return err
 		}
 
@@ -63,6 +65,12 @@ func GetFavourites(service favourites.Service) http.HandlerFunc {
 
 		favourites, err := service.GetFavouritesForUserConference(session.UserID, int32(conferenceID))
 		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			}
 			return err
 		}
 
@@ -88,36 +96,385 @@ func DeleteFavourite(service favourites.Service) http.HandlerFunc {
 			return err
 		}
 
-		if request.GUID == nil && request.EventID == nil {
+		event, err := parseEventReference(dto.FavouriteReference{GUID: request.GUID, EventID: request.EventID})
+		if err != nil {
+			return err
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		err = service.DeleteFavouriteForUserByEventDetails(session.UserID, event.EventGUID, event.EventID, request.ConferenceID)
+		if err != nil {
+			if err == favourites.ErrNotFound {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Favourite not found",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+		}
+	})
+}
+
+func UpdateFavourite(service favourites.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		favouriteID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
 			return &dto.ErrorResponse{
 				Code:    http.StatusBadRequest,
-				Message: "One of event GUID or event ID must be specified",
+				Message: "Bad favourite ID",
 			}
 		}
 
+		var request dto.UpdateFavouriteRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
 		session := r.Context().Value("session").(*session.UserSession)
-		var err error
-		var uuid pgtype.UUID
-		if err := uuid.Scan(*request.GUID); err != nil {
+
+		favourite, err := service.UpdateFavouriteForUser(session.UserID, int32(favouriteID), favourites.FavouriteUpdate{
+			Note:       request.Note,
+			Priority:   request.Priority,
+			Attendance: request.Attendance,
+		})
+		if err != nil {
+			if errors.Is(err, favourites.ErrNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Favourite not found",
+				}
+			}
+			return err
+		}
+
+		var response dto.GetFavouritesResponse
+		response.Scan(*favourite)
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func GetFavouriteConflicts(service favourites.Service, conflictBuffer time.Duration) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
 			return &dto.ErrorResponse{
 				Code:    http.StatusBadRequest,
-				Message: "Bad event GUID",
+				Message: "Bad conference ID",
 			}
 		}
 
-		err = service.DeleteFavouriteForUserByEventDetails(session.UserID, uuid, request.EventID, request.ConferenceID)
+		buffer := conflictBuffer
+		if r.URL.Query().Has("buffer") {
+			minutes, err := strconv.Atoi(r.URL.Query().Get("buffer"))
+			if err != nil || minutes < 0 {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Bad buffer",
+				}
+			}
+			buffer = time.Duration(minutes) * time.Minute
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		conflicts, err := service.GetConflictsForUserConference(session.UserID, int32(conferenceID), buffer)
 		if err != nil {
-			if err == favourites.ErrNotFound {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
 				return &dto.ErrorResponse{
 					Code:    http.StatusNotFound,
-					Message: "Favourite not found",
+					Message: "Conference not found",
 				}
 			}
 			return err
 		}
 
+		conflictsResponse := make([][]dto.FavouriteConflictResponse, 0)
+		for _, conflict := range conflicts {
+			groupResponse := make([]dto.FavouriteConflictResponse, 0)
+			for _, favouriteEvent := range conflict {
+				var eventResponse dto.FavouriteConflictResponse
+				eventResponse.Scan(favouriteEvent)
+
+				groupResponse = append(groupResponse, eventResponse)
+			}
+			conflictsResponse = append(conflictsResponse, groupResponse)
+		}
+
 		return &dto.OkResponse{
 			Code: http.StatusOK,
+			Data: conflictsResponse,
 		}
 	})
 }
+
+func CreateFavourites(service favourites.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.BatchFavouritesRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		events, err := parseEventReferences(request.Events)
+		if err != nil {
+			return err
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		createdFavourites, err := service.CreateFavouritesForUser(session.UserID, request.ConferenceID, events, false)
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			}
+			return err
+		}
+
+		favouritesResponse := make([]dto.GetFavouritesResponse, 0)
+		for _, favourite := range createdFavourites {
+			var favouriteResponse dto.GetFavouritesResponse
+			favouriteResponse.Scan(favourite)
+
+			favouritesResponse = append(favouritesResponse, favouriteResponse)
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusCreated,
+			Data: favouritesResponse,
+		}
+	})
+}
+
+func DeleteFavourites(service favourites.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.BatchFavouritesRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		events, err := parseEventReferences(request.Events)
+		if err != nil {
+			return err
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		deleted, err := service.DeleteFavouritesForUser(session.UserID, request.ConferenceID, events)
+		if err != nil {
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: &dto.BatchDeleteFavouritesResponse{
+				Deleted: deleted,
+			},
+		}
+	})
+}
+
+func ExportFavourites(service favourites.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			dto.WriteDto(w, r, &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			})
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = "json"
+		}
+		if format != "json" && format != "csv" {
+			dto.WriteDto(w, r, &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Format must be one of json or csv",
+			})
+			return
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		favourites, err := service.GetFavouritesForUserConference(session.UserID, int32(conferenceID))
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				err = &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			}
+			dto.WriteDto(w, r, err)
+			return
+		}
+
+		export := dto.FavouritesExport{
+			ConferenceID: int32(conferenceID),
+			ExportedAt:   time.Now(),
+			Favourites:   make([]dto.ExportedFavourite, 0),
+		}
+		for _, favourite := range *favourites {
+			var exportedFavourite dto.ExportedFavourite
+			exportedFavourite.Scan(favourite)
+
+			export.Favourites = append(export.Favourites, exportedFavourite)
+		}
+
+		filename := fmt.Sprintf("favourites-%d.%s", conferenceID, format)
+		w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+
+		if format == "csv" {
+			w.Header().Add("Content-Type", "text/csv")
+			if err := dto.WriteFavouritesCSV(w, export.Favourites); err != nil {
+				slog.Error("could not write CSV", "error", err)
+			}
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(export); err != nil {
+			slog.Error("could not serialise JSON", "error", err)
+		}
+	}
+}
+
+func ImportFavourites(service favourites.Service, agendaService agenda.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		var imported []dto.ExportedFavourite
+		if slug := r.URL.Query().Get("share"); slug != "" {
+			sharedAgenda, err := agendaService.GetSharedAgenda(slug)
+			if err != nil {
+				if errors.Is(err, agenda.ErrShareNotFound) {
+					return &dto.ErrorResponse{
+						Code:    http.StatusNotFound,
+						Message: "Shared agenda not found",
+					}
+				}
+				return err
+			}
+			if sharedAgenda.Share.ConferenceID != int32(conferenceID) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Shared agenda is for a different conference",
+				}
+			}
+
+			for _, event := range sharedAgenda.Events {
+				guid := event.GUID
+				eventID := event.ID
+				imported = append(imported, dto.ExportedFavourite{
+					GUID:    &guid,
+					EventID: &eventID,
+				})
+			}
+		} else if r.URL.Query().Get("format") == "csv" || strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+			if err := dto.ReadFavouritesCSV(r, &imported); err != nil {
+				return err
+			}
+		} else {
+			var request dto.FavouritesExport
+			if err := dto.ReadDto(r, &request); err != nil {
+				return err
+			}
+			imported = request.Favourites
+		}
+
+		events := make([]favourites.EventReference, 0, len(imported))
+		for _, favourite := range imported {
+			event, err := parseEventReference(dto.FavouriteReference{
+				GUID:    favourite.GUID,
+				EventID: favourite.EventID,
+			})
+			if err != nil {
+				return err
+			}
+			event.Note = favourite.Note
+			event.Priority = favourite.Priority
+			event.Attendance = favourite.Attendance
+
+			events = append(events, event)
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		createdFavourites, err := service.CreateFavouritesForUser(session.UserID, int32(conferenceID), events, r.URL.Query().Get("replace") == "true")
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			}
+			return err
+		}
+
+		favouritesResponse := make([]dto.GetFavouritesResponse, 0)
+		for _, favourite := range createdFavourites {
+			var favouriteResponse dto.GetFavouritesResponse
+			favouriteResponse.Scan(favourite)
+
+			favouritesResponse = append(favouritesResponse, favouriteResponse)
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusCreated,
+			Data: favouritesResponse,
+		}
+	})
+}
+
+func parseEventReferences(references []dto.FavouriteReference) ([]favourites.EventReference, error) {
+	events := make([]favourites.EventReference, 0, len(references))
+	for _, reference := range references {
+		event, err := parseEventReference(reference)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// parseEventReference reads the event a request refers to, which must be
+// given by GUID, ID or both.
+func parseEventReference(reference dto.FavouriteReference) (favourites.EventReference, error) {
+	var event favourites.EventReference
+	if reference.GUID == nil && reference.EventID == nil {
+		return event, &dto.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "One of event GUID or event ID must be specified",
+		}
+	}
+
+	if reference.GUID != nil {
+		if err := event.EventGUID.Scan(*reference.GUID); err != nil {
+			return event, &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad event GUID",
+			}
+		}
+	}
+	event.EventID = reference.EventID
+
+	return event, nil
+}
@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/LMBishop/confplanner/api/dto"
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/group"
+	"github.com/LMBishop/confplanner/pkg/session"
+)
+
+func GetGroups(service group.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		session := r.Context().Value("session").(*session.UserSession)
+
+		groups, err := service.GetGroupsForUser(session.UserID)
+		if err != nil {
+			return err
+		}
+
+		response := make([]dto.GroupResponse, 0, len(groups))
+		for _, g := range groups {
+			var groupResponse dto.GroupResponse
+			groupResponse.Scan(g)
+			response = append(response, groupResponse)
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func CreateGroup(service group.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.CreateGroupRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		createdGroup, err := service.CreateGroup(session.UserID, request.Name)
+		if err != nil {
+			return err
+		}
+
+		var response dto.GroupResponse
+		response.Scan(*createdGroup)
+		return &dto.OkResponse{
+			Code: http.StatusCreated,
+			Data: response,
+		}
+	})
+}
+
+func GetGroupMembers(service group.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		groupID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad group ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		members, err := service.GetMembers(session.UserID, int32(groupID))
+		if err != nil {
+			if errors.Is(err, group.ErrGroupNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Group not found",
+				}
+			}
+			return err
+		}
+
+		response := make([]dto.GroupMemberResponse, 0, len(members))
+		for _, member := range members {
+			var memberResponse dto.GroupMemberResponse
+			memberResponse.Scan(member)
+			response = append(response, memberResponse)
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func InviteGroupMember(service group.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		groupID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad group ID",
+			}
+		}
+
+		var request dto.InviteGroupMemberRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		err = service.InviteUser(session.UserID, int32(groupID), request.Username)
+		if err != nil {
+			if errors.Is(err, group.ErrGroupNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Group not found",
+				}
+			} else if errors.Is(err, group.ErrUserNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "User not found",
+				}
+			} else if errors.Is(err, group.ErrAlreadyMember) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusConflict,
+					Message: "User is already a member of this group",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusCreated,
+		}
+	})
+}
+
+func GetGroupInvitations(service group.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		session := r.Context().Value("session").(*session.UserSession)
+
+		invitations, err := service.GetInvitationsForUser(session.UserID)
+		if err != nil {
+			return err
+		}
+
+		response := make([]dto.GroupInvitationResponse, 0, len(invitations))
+		for _, invitation := range invitations {
+			var invitationResponse dto.GroupInvitationResponse
+			invitationResponse.Scan(invitation)
+			response = append(response, invitationResponse)
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func DeclineGroupInvitation(service group.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		groupID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad group ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		err = service.DeclineInvitation(session.UserID, int32(groupID))
+		if err != nil {
+			if errors.Is(err, group.ErrInvitationNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Invitation not found",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+		}
+	})
+}
+
+func JoinGroup(service group.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		groupID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad group ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		err = service.JoinGroup(session.UserID, int32(groupID))
+		if err != nil {
+			if errors.Is(err, group.ErrInvitationNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Invitation not found",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+		}
+	})
+}
+
+func LeaveGroup(service group.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		groupID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad group ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		err = service.LeaveGroup(session.UserID, int32(groupID))
+		if err != nil {
+			if errors.Is(err, group.ErrGroupNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Group not found",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+		}
+	})
+}
+
+func GetGroupAgenda(service group.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		groupID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad group ID",
+			}
+		}
+
+		conferenceID, err := strconv.Atoi(r.PathValue("conference"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		events, err := service.GetGroupAgenda(session.UserID, int32(groupID), int32(conferenceID))
+		if err != nil {
+			if errors.Is(err, group.ErrGroupNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Group not found",
+				}
+			} else if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			}
+			return err
+		}
+
+		response := make([]dto.GroupAgendaEventResponse, 0, len(events))
+		for _, event := range events {
+			var eventResponse dto.GroupAgendaEventResponse
+			eventResponse.Scan(event)
+			response = append(response, eventResponse)
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"crypto/subtle"
 	"net/http"
+	"time"
 
 	"github.com/LMBishop/confplanner/api/dto"
 	"github.com/LMBishop/confplanner/pkg/calendar"
@@ -36,12 +37,17 @@ func GetIcal(icalService ical.Service, calendarService calendar.Service) http.Ha
 			return
 		}
 
-		ical, err := icalService.GenerateIcalForCalendar(*calendar)
+		ical, etag, err := icalService.GenerateIcalForCalendar(*calendar)
 		if err != nil {
 			dto.WriteDto(w, r, err)
 			return
 		}
 
+		if notModified(w, r, etag, time.Time{}) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
 		w.Header().Add("Content-Type", "text/calendar")
 		w.Write([]byte(ical))
 	}
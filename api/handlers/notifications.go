@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/LMBishop/confplanner/api/dto"
+	"github.com/LMBishop/confplanner/pkg/notification"
+	"github.com/LMBishop/confplanner/pkg/session"
+)
+
+func GetNotifications(service notification.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		limit := 50
+		if r.URL.Query().Has("limit") {
+			var err error
+			limit, err = strconv.Atoi(r.URL.Query().Get("limit"))
+			if err != nil || limit < 1 || limit > 200 {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Bad limit",
+				}
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		notifications, err := service.GetNotificationsForUser(session.UserID, r.URL.Query().Get("unread") == "true", int32(limit))
+		if err != nil {
+			return err
+		}
+
+		response := make([]dto.NotificationResponse, 0, len(notifications))
+		for _, n := range notifications {
+			var notificationResponse dto.NotificationResponse
+			notificationResponse.Scan(n)
+			response = append(response, notificationResponse)
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func MarkNotificationRead(service notification.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		notificationID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad notification ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		err = service.MarkRead(session.UserID, int32(notificationID))
+		if err != nil {
+			if errors.Is(err, notification.ErrNotificationNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Notification not found",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+		}
+	})
+}
+
+func MarkAllNotificationsRead(service notification.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		session := r.Context().Value("session").(*session.UserSession)
+
+		updated, err := service.MarkAllRead(session.UserID)
+		if err != nil {
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: &dto.MarkAllNotificationsReadResponse{
+				Updated: updated,
+			},
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/LMBishop/confplanner/api/dto"
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/session"
+)
+
+func GetEventOverrides(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		overrides, err := service.GetOverrides(int32(conferenceID))
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			}
+			return err
+		}
+
+		response := make([]dto.EventOverrideResponse, len(overrides))
+		for i, override := range overrides {
+			response[i].Scan(override)
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func SetEventOverride(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.SetEventOverrideRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		override, err := service.SetOverride(int32(conferenceID), r.PathValue("guid"), conference.Override{
+			Start:     request.Start,
+			End:       request.End,
+			Room:      request.Room,
+			Title:     request.Title,
+			Cancelled: request.Cancelled,
+			Note:      request.Note,
+		}, session.UserID)
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			} else if errors.Is(err, conference.ErrEventNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Event not found",
+				}
+			} else if errors.Is(err, conference.ErrInvalidOverride) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: err.Error(),
+				}
+			}
+			return err
+		}
+
+		var response dto.EventOverrideResponse
+		response.Scan(*override)
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func DeleteEventOverride(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		if err := service.DeleteOverride(int32(conferenceID), r.PathValue("guid")); err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			} else if errors.Is(err, conference.ErrOverrideNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Override not found",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+		}
+	})
+}
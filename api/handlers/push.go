@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/LMBishop/confplanner/api/dto"
+	"github.com/LMBishop/confplanner/pkg/push"
+	"github.com/LMBishop/confplanner/pkg/session"
+)
+
+func GetPushKey(service push.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: &dto.PushKeyResponse{
+				PublicKey: service.PublicKey(),
+			},
+		}
+	})
+}
+
+func CreatePushSubscription(service push.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.CreatePushSubscriptionRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		subscription, err := service.Subscribe(session.UserID, push.Subscription{
+			Endpoint: request.Endpoint,
+			P256dh:   request.Keys.P256dh,
+			Auth:     request.Keys.Auth,
+		})
+		if err != nil {
+			if errors.Is(err, push.ErrBadEndpoint) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Bad push subscription",
+				}
+			}
+			return err
+		}
+
+		var response dto.PushSubscriptionResponse
+		response.Scan(*subscription)
+
+		return &dto.OkResponse{
+			Code: http.StatusCreated,
+			Data: response,
+		}
+	})
+}
+
+func DeletePushSubscription(service push.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.DeletePushSubscriptionRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		if err := service.Unsubscribe(session.UserID, request.Endpoint); err != nil {
+			if errors.Is(err, push.ErrSubscriptionNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Subscription not found",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+		}
+	})
+}
+
+func SendTestPush(service push.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		session := r.Context().Value("session").(*session.UserSession)
+
+		err := service.SendToUser(session.UserID, push.Message{
+			Title: "confplanner",
+			Body:  "Notifications are working",
+			Tag:   "test",
+		})
+		if err != nil {
+			if errors.Is(err, push.ErrSubscriptionNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "No push subscriptions",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+		}
+	})
+}
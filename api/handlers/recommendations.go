@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/LMBishop/confplanner/api/dto"
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/recommendation"
+	"github.com/LMBishop/confplanner/pkg/session"
+)
+
+func GetRecommendations(service recommendation.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		limit := 10
+		if r.URL.Query().Has("limit") {
+			limit, err = strconv.Atoi(r.URL.Query().Get("limit"))
+			if err != nil || limit < 1 || limit > 50 {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Bad limit",
+				}
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		recommendations, err := service.GetRecommendationsForUser(session.UserID, int32(conferenceID), limit)
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			}
+			return err
+		}
+
+		response := make([]dto.RecommendationResponse, 0, len(recommendations))
+		for _, recommendation := range recommendations {
+			var recommendationResponse dto.RecommendationResponse
+			recommendationResponse.Scan(recommendation)
+			response = append(response, recommendationResponse)
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
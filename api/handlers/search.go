@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/LMBishop/confplanner/api/dto"
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/search"
+	"github.com/LMBishop/confplanner/pkg/session"
+)
+
+func SearchEvents(service search.Service, conferenceService conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+		if err := checkConferenceAccess(conferenceService, int32(conferenceID), session); err != nil {
+			return err
+		}
+
+		query := r.URL.Query().Get("q")
+		if query == "" || len(query) > 256 {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad query",
+			}
+		}
+
+		limit := 25
+		if r.URL.Query().Has("limit") {
+			limit, err = strconv.Atoi(r.URL.Query().Get("limit"))
+			if err != nil || limit < 1 || limit > 100 {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Bad limit",
+				}
+			}
+		}
+
+		results, err := service.Search(int32(conferenceID), query, limit)
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			}
+			return err
+		}
+
+		response := make([]dto.SearchResultResponse, 0, len(results))
+		for _, result := range results {
+			var resultResponse dto.SearchResultResponse
+			resultResponse.Scan(result)
+			response = append(response, resultResponse)
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/LMBishop/confplanner/api/dto"
+	"github.com/LMBishop/confplanner/pkg/conference"
+)
+
+func GetSources(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		response, err := sourcesResponse(service, int32(conferenceID))
+		if err != nil {
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func AddSource(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.AddSourceRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		if _, err := service.AddSource(int32(conferenceID), request.URL, request.Label); err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			} else if errors.Is(err, conference.ErrScheduleFetch) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Could not fetch schedule from URL (is it a valid pentabarf XML file?)",
+				}
+			} else if errors.Is(err, conference.ErrDuplicateSource) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusConflict,
+					Message: err.Error(),
+				}
+			}
+			return err
+		}
+
+		// the response includes any conflicts the new source brings
+		response, err := sourcesResponse(service, int32(conferenceID))
+		if err != nil {
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusCreated,
+			Data: response,
+		}
+	})
+}
+
+func DeleteSource(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		sourceID, err := strconv.Atoi(r.PathValue("sourceID"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad source ID",
+			}
+		}
+
+		if err := service.DeleteSource(int32(conferenceID), int32(sourceID)); err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			} else if errors.Is(err, conference.ErrSourceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Source not found",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+		}
+	})
+}
+
+func sourcesResponse(service conference.Service, conferenceID int32) (*dto.GetSourcesResponse, error) {
+	sources, conflicts, err := service.GetSources(conferenceID)
+	if err != nil {
+		if errors.Is(err, conference.ErrConferenceNotFound) {
+			return nil, &dto.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Conference not found",
+			}
+		}
+		return nil, err
+	}
+
+	response := &dto.GetSourcesResponse{
+		Sources:   make([]dto.SourceResponse, len(sources)),
+		Conflicts: make([]dto.MergeConflictResponse, len(conflicts)),
+	}
+	for i, source := range sources {
+		response.Sources[i].Scan(source)
+	}
+	for i, conflict := range conflicts {
+		response.Conflicts[i].Scan(conflict)
+	}
+
+	return response, nil
+}
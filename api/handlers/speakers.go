@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/LMBishop/confplanner/api/dto"
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/session"
+	"github.com/LMBishop/confplanner/pkg/speaker"
+	"github.com/golang-cz/nilslice"
+)
+
+func GetSpeakers(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+		if err := checkConferenceAccess(service, int32(conferenceID), session); err != nil {
+			return err
+		}
+
+		speakers, err := service.GetSpeakers(int32(conferenceID))
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			}
+			return err
+		}
+
+		response := make([]dto.SpeakerResponse, 0, len(speakers))
+		for _, s := range speakers {
+			var speakerResponse dto.SpeakerResponse
+			speakerResponse.Scan(s)
+			response = append(response, speakerResponse)
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func GetSpeaker(service conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		personID, err := strconv.Atoi(r.PathValue("personID"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad speaker ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+		if err := checkConferenceAccess(service, int32(conferenceID), session); err != nil {
+			return err
+		}
+
+		s, err := service.GetSpeaker(int32(conferenceID), personID)
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) || errors.Is(err, conference.ErrSpeakerNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Speaker not found",
+				}
+			}
+			return err
+		}
+
+		response := dto.SpeakerDetailResponse{
+			Events: nilslice.Initialize(s.Events),
+		}
+		response.SpeakerResponse.Scan(*s)
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func FollowSpeaker(service speaker.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		personID, err := strconv.Atoi(r.PathValue("personID"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad speaker ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		follow, err := service.FollowSpeaker(session.UserID, int32(conferenceID), personID)
+		if err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) || errors.Is(err, conference.ErrSpeakerNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Speaker not found",
+				}
+			} else if errors.Is(err, speaker.ErrSpeakerHasNoID) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: err.Error(),
+				}
+			}
+			return err
+		}
+
+		var response dto.SpeakerFollowResponse
+		response.Scan(*follow)
+		return &dto.OkResponse{
+			Code: http.StatusCreated,
+			Data: response,
+		}
+	})
+}
+
+func UnfollowSpeaker(service speaker.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		personID, err := strconv.Atoi(r.PathValue("personID"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad speaker ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		err = service.UnfollowSpeaker(session.UserID, int32(conferenceID), personID)
+		if err != nil {
+			if errors.Is(err, speaker.ErrFollowNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Not following this speaker",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+		}
+	})
+}
+
+func GetSpeakerFollows(service speaker.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		session := r.Context().Value("session").(*session.UserSession)
+
+		follows, err := service.GetFollowsForUser(session.UserID)
+		if err != nil {
+			return err
+		}
+
+		response := make([]dto.SpeakerFollowResponse, 0, len(follows))
+		for _, follow := range follows {
+			var followResponse dto.SpeakerFollowResponse
+			followResponse.Scan(follow)
+			response = append(response, followResponse)
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
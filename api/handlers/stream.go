@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/LMBishop/confplanner/api/dto"
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/live"
+	"github.com/LMBishop/confplanner/pkg/session"
+)
+
+// how often a comment is sent on an idle stream, so proxies don't time out
+// the connection and clients notice when it has gone away
+const heartbeatInterval = 25 * time.Second
+
+func StreamConference(hub live.Hub, conferenceService conference.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			dto.WriteDto(w, r, &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			})
+			return
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+		if err := checkConferenceAccess(conferenceService, int32(conferenceID), session); err != nil {
+			dto.WriteDto(w, r, err)
+			return
+		}
+
+		if _, _, err := conferenceService.GetSchedule(int32(conferenceID)); err != nil {
+			if errors.Is(err, conference.ErrConferenceNotFound) {
+				err = &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Conference not found",
+				}
+			}
+			dto.WriteDto(w, r, err)
+			return
+		}
+
+		// EventSource sends the ID of the last message it saw when it
+		// reconnects; fetch-based clients may pass it as a query parameter
+		lastEventID := r.Header.Get("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = r.URL.Query().Get("lastEventId")
+		}
+
+		subscription, missed := hub.Subscribe(int32(conferenceID), session.UserID, lastEventID)
+		defer hub.Unsubscribe(subscription)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		rc := http.NewResponseController(w)
+		// the stream is expected to outlive the server's write timeout
+		rc.SetWriteDeadline(time.Time{})
+
+		for _, message := range missed {
+			writeMessage(w, message)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case message, ok := <-subscription.Messages:
+				if !ok {
+					// too far behind; the client will reconnect and catch up
+					return
+				}
+				writeMessage(w, message)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeMessage(w http.ResponseWriter, message live.Message) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", message.ID, message.Event, message.Data)
+}
@@ -2,12 +2,25 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"time"
 
 	"github.com/LMBishop/confplanner/api/dto"
+	"github.com/LMBishop/confplanner/pkg/agenda"
 	"github.com/LMBishop/confplanner/pkg/auth"
+	"github.com/LMBishop/confplanner/pkg/calendar"
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/favourites"
+	"github.com/LMBishop/confplanner/pkg/group"
+	"github.com/LMBishop/confplanner/pkg/mail"
+	"github.com/LMBishop/confplanner/pkg/notification"
+	"github.com/LMBishop/confplanner/pkg/push"
 	"github.com/LMBishop/confplanner/pkg/session"
+	"github.com/LMBishop/confplanner/pkg/speaker"
 	"github.com/LMBishop/confplanner/pkg/user"
+	"github.com/LMBishop/confplanner/pkg/webhook"
 )
 
 func Register(userService user.Service, authService auth.Service) http.HandlerFunc {
@@ -65,3 +78,277 @@ func Logout(store session.Service) http.HandlerFunc {
 		}
 	})
 }
+
+func ExportUser(userService user.Service, favouritesService favourites.Service, conferenceService conference.Service, calendarService calendar.Service, agendaService agenda.Service, groupService group.Service, speakerService speaker.Service, notificationService notification.Service, pushService push.Service, mailService mail.Service, webhookService webhook.Service, store session.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		session := r.Context().Value("session").(*session.UserSession)
+
+		u, err := userService.GetUserByID(session.UserID)
+		if err != nil {
+			return err
+		}
+
+		profile, err := userService.GetProfile(session.UserID)
+		if err != nil {
+			return err
+		}
+
+		favourites, err := favouritesService.GetAllFavouritesForUser(session.UserID)
+		if err != nil {
+			return err
+		}
+
+		response := &dto.ExportUserResponse{
+			ExportedAt:        time.Now(),
+			Favourites:        make([]dto.ExportFavourite, 0),
+			CustomEvents:      make([]dto.ExportCustomEvent, 0),
+			Shares:            make([]dto.ExportAgendaShare, 0),
+			Groups:            make([]dto.GroupResponse, 0),
+			GroupInvitations:  make([]dto.GroupInvitationResponse, 0),
+			Follows:           make([]dto.SpeakerFollowResponse, 0),
+			Notifications:     make([]dto.NotificationResponse, 0),
+			PushSubscriptions: make([]dto.PushSubscriptionResponse, 0),
+			EmailDigests:      make([]dto.ExportEmailDigest, 0),
+			Webhooks:          make([]dto.ExportWebhook, 0),
+			Sessions:          make([]dto.ExportSession, 0),
+		}
+		response.User.Scan(*u)
+		response.Profile.Scan(*profile)
+
+		for _, favourite := range *favourites {
+			var exportFavourite dto.ExportFavourite
+			exportFavourite.Scan(favourite)
+			response.Favourites = append(response.Favourites, exportFavourite)
+		}
+
+		customEvents, err := conferenceService.GetCustomEventsOwnedByUser(session.UserID)
+		if err != nil {
+			return err
+		}
+		for _, customEvent := range customEvents {
+			var exportCustomEvent dto.ExportCustomEvent
+			exportCustomEvent.Scan(customEvent)
+			response.CustomEvents = append(response.CustomEvents, exportCustomEvent)
+		}
+
+		cal, err := calendarService.GetCalendarForUser(session.UserID)
+		if err != nil && !errors.Is(err, calendar.ErrCalendarNotFound) {
+			return err
+		}
+		if cal != nil {
+			response.Calendar = &dto.ExportCalendar{}
+			response.Calendar.Scan(*cal)
+		}
+
+		shares, err := agendaService.GetSharesForUser(session.UserID)
+		if err != nil {
+			return err
+		}
+		for _, share := range shares {
+			var exportShare dto.ExportAgendaShare
+			exportShare.Scan(share)
+			response.Shares = append(response.Shares, exportShare)
+		}
+
+		groups, err := groupService.GetGroupsForUser(session.UserID)
+		if err != nil {
+			return err
+		}
+		for _, g := range groups {
+			var exportGroup dto.GroupResponse
+			exportGroup.Scan(g)
+			response.Groups = append(response.Groups, exportGroup)
+		}
+
+		invitations, err := groupService.GetInvitationsForUser(session.UserID)
+		if err != nil {
+			return err
+		}
+		for _, invitation := range invitations {
+			var exportInvitation dto.GroupInvitationResponse
+			exportInvitation.Scan(invitation)
+			response.GroupInvitations = append(response.GroupInvitations, exportInvitation)
+		}
+
+		follows, err := speakerService.GetFollowsForUser(session.UserID)
+		if err != nil {
+			return err
+		}
+		for _, follow := range follows {
+			var exportFollow dto.SpeakerFollowResponse
+			exportFollow.Scan(follow)
+			response.Follows = append(response.Follows, exportFollow)
+		}
+
+		notifications, err := notificationService.GetNotificationsForUser(session.UserID, false, math.MaxInt32)
+		if err != nil {
+			return err
+		}
+		for _, n := range notifications {
+			var exportNotification dto.NotificationResponse
+			exportNotification.Scan(n)
+			response.Notifications = append(response.Notifications, exportNotification)
+		}
+
+		subscriptions, err := pushService.GetSubscriptionsForUser(session.UserID)
+		if err != nil {
+			return err
+		}
+		for _, subscription := range subscriptions {
+			var exportSubscription dto.PushSubscriptionResponse
+			exportSubscription.Scan(subscription)
+			response.PushSubscriptions = append(response.PushSubscriptions, exportSubscription)
+		}
+
+		digests, err := mailService.GetDigestsForUser(session.UserID)
+		if err != nil {
+			return err
+		}
+		for _, digest := range digests {
+			var exportDigest dto.ExportEmailDigest
+			exportDigest.Scan(digest)
+			response.EmailDigests = append(response.EmailDigests, exportDigest)
+		}
+
+		webhooks, err := webhookService.GetUserWebhooks(session.UserID)
+		if err != nil {
+			return err
+		}
+		for _, hook := range webhooks {
+			deliveries, err := webhookService.GetDeliveries(hook.ID)
+			if err != nil {
+				return err
+			}
+			exportWebhook := dto.ExportWebhook{
+				Deliveries: make([]dto.WebhookDeliveryResponse, 0, len(deliveries)),
+			}
+			exportWebhook.WebhookResponse.Scan(hook)
+			for _, delivery := range deliveries {
+				exportWebhook.Deliveries = append(exportWebhook.Deliveries, deliveryResponse(&hook, delivery))
+			}
+			response.Webhooks = append(response.Webhooks, exportWebhook)
+		}
+
+		for _, s := range store.GetByUserID(session.UserID) {
+			var exportSession dto.ExportSession
+			exportSession.Scan(*s)
+			response.Sessions = append(response.Sessions, exportSession)
+		}
+
+		w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"confplanner-%s.json\"", u.Username))
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func DeleteUser(userService user.Service, store session.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.DeleteUserRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		if request.Username != session.Username {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Username does not match the signed in user",
+			}
+		}
+
+		err := userService.DeleteUser(session.UserID, request.Password, session.LoginTime)
+		if err != nil {
+			if errors.Is(err, user.ErrIncorrectPassword) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusForbidden,
+					Message: "Incorrect password",
+				}
+			} else if errors.Is(err, user.ErrReauthenticationRequired) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusForbidden,
+					Message: "Sign in again to delete your account",
+				}
+			} else if errors.Is(err, user.ErrUserNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "User not found",
+				}
+			}
+
+			return err
+		}
+
+		if err := store.DestroyByUserID(session.UserID); err != nil {
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusNoContent,
+		}
+	})
+}
+
+func GetUserProfile(userService user.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		session := r.Context().Value("session").(*session.UserSession)
+
+		profile, err := userService.GetProfile(session.UserID)
+		if err != nil {
+			return err
+		}
+
+		var response dto.UserProfileResponse
+		response.Scan(*profile)
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func UpdateUserProfile(userService user.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.UpdateUserProfileRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+
+		profile, err := userService.UpdateProfile(session.UserID, user.ProfileUpdate{
+			DisplayName:          request.DisplayName,
+			TimeZone:             request.TimeZone,
+			Clock:                request.Clock,
+			Language:             request.Language,
+			DefaultConferenceID:  request.DefaultConferenceID,
+			FavouritesVisibility: request.FavouritesVisibility,
+			EmailDigest:          request.EmailDigest,
+			EmailAlerts:          request.EmailAlerts,
+		})
+		if err != nil {
+			if errors.Is(err, user.ErrInvalidTimeZone) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Unknown time zone",
+				}
+			} else if errors.Is(err, user.ErrConferenceNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusBadRequest,
+					Message: "Default conference does not exist",
+				}
+			}
+
+			return err
+		}
+
+		var response dto.UserProfileResponse
+		response.Scan(*profile)
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/LMBishop/confplanner/api/dto"
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/LMBishop/confplanner/pkg/session"
+	"github.com/LMBishop/confplanner/pkg/webhook"
+)
+
+// webhookLookup finds the webhook a request refers to, returning an error
+// response if it doesn't exist or isn't reachable through the route used.
+type webhookLookup func(service webhook.Service, r *http.Request) (*sqlc.Webhook, error)
+
+func GetConferenceWebhooks(service webhook.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		webhooks, err := service.GetConferenceWebhooks(int32(conferenceID))
+		if err != nil {
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: webhooksResponse(webhooks),
+		}
+	})
+}
+
+func CreateConferenceWebhook(service webhook.Service, conferenceService conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.CreateWebhookRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		conferenceID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad conference ID",
+			}
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+		if err := checkConferenceAccess(conferenceService, int32(conferenceID), session); err != nil {
+			return err
+		}
+
+		return createWebhook(service, session.UserID, int32(conferenceID), webhook.ScopeConference, request.URL, request.Events)
+	})
+}
+
+func DeleteConferenceWebhook(service webhook.Service) http.HandlerFunc {
+	return deleteWebhook(service, conferenceWebhook)
+}
+
+func GetConferenceWebhookDeliveries(service webhook.Service) http.HandlerFunc {
+	return getWebhookDeliveries(service, conferenceWebhook)
+}
+
+func RedeliverConferenceWebhookDelivery(service webhook.Service) http.HandlerFunc {
+	return redeliverWebhookDelivery(service, conferenceWebhook)
+}
+
+func GetUserWebhooks(service webhook.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		session := r.Context().Value("session").(*session.UserSession)
+
+		webhooks, err := service.GetUserWebhooks(session.UserID)
+		if err != nil {
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: webhooksResponse(webhooks),
+		}
+	})
+}
+
+func CreateUserWebhook(service webhook.Service, conferenceService conference.Service) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var request dto.CreateUserWebhookRequest
+		if err := dto.ReadDto(r, &request); err != nil {
+			return err
+		}
+
+		session := r.Context().Value("session").(*session.UserSession)
+		if err := checkConferenceAccess(conferenceService, request.ConferenceID, session); err != nil {
+			return err
+		}
+
+		return createWebhook(service, session.UserID, request.ConferenceID, webhook.ScopeUser, request.URL, request.Events)
+	})
+}
+
+func DeleteUserWebhook(service webhook.Service) http.HandlerFunc {
+	return deleteWebhook(service, userWebhook)
+}
+
+func GetUserWebhookDeliveries(service webhook.Service) http.HandlerFunc {
+	return getWebhookDeliveries(service, userWebhook)
+}
+
+func RedeliverUserWebhookDelivery(service webhook.Service) http.HandlerFunc {
+	return redeliverWebhookDelivery(service, userWebhook)
+}
+
+// createWebhook creates a webhook for a conference the caller has already
+// checked exists.
+func createWebhook(service webhook.Service, userID int32, conferenceID int32, scope string, url string, events []string) error {
+	created, err := service.CreateWebhook(userID, conferenceID, scope, url, events)
+	if err != nil {
+		if errors.Is(err, webhook.ErrBadURL) || errors.Is(err, webhook.ErrBadEvent) {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+		}
+		return err
+	}
+
+	var response dto.WebhookResponse
+	response.Scan(*created)
+	response.Secret = created.Secret
+
+	return &dto.OkResponse{
+		Code: http.StatusCreated,
+		Data: response,
+	}
+}
+
+func deleteWebhook(service webhook.Service, lookup webhookLookup) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		found, err := lookup(service, r)
+		if err != nil {
+			return err
+		}
+
+		if err := service.DeleteWebhook(found.ID); err != nil {
+			if errors.Is(err, webhook.ErrWebhookNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Webhook not found",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+		}
+	})
+}
+
+func getWebhookDeliveries(service webhook.Service, lookup webhookLookup) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		found, err := lookup(service, r)
+		if err != nil {
+			return err
+		}
+
+		deliveries, err := service.GetDeliveries(found.ID)
+		if err != nil {
+			return err
+		}
+
+		response := make([]dto.WebhookDeliveryResponse, len(deliveries))
+		for i, delivery := range deliveries {
+			response[i] = deliveryResponse(found, delivery)
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusOK,
+			Data: response,
+		}
+	})
+}
+
+func redeliverWebhookDelivery(service webhook.Service, lookup webhookLookup) http.HandlerFunc {
+	return dto.WrapResponseFunc(func(w http.ResponseWriter, r *http.Request) error {
+		found, err := lookup(service, r)
+		if err != nil {
+			return err
+		}
+
+		deliveryID, err := strconv.Atoi(r.PathValue("deliveryID"))
+		if err != nil {
+			return &dto.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Bad delivery ID",
+			}
+		}
+
+		delivery, err := service.Redeliver(found.ID, int32(deliveryID))
+		if err != nil {
+			if errors.Is(err, webhook.ErrDeliveryNotFound) {
+				return &dto.ErrorResponse{
+					Code:    http.StatusNotFound,
+					Message: "Delivery not found",
+				}
+			}
+			return err
+		}
+
+		return &dto.OkResponse{
+			Code: http.StatusAccepted,
+			Data: deliveryResponse(found, *delivery),
+		}
+	})
+}
+
+// conferenceWebhook finds a conference's webhook from the conference and
+// webhook IDs in the path.
+func conferenceWebhook(service webhook.Service, r *http.Request) (*sqlc.Webhook, error) {
+	conferenceID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return nil, &dto.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Bad conference ID",
+		}
+	}
+
+	found, err := findWebhook(service, r.PathValue("webhookID"))
+	if err != nil {
+		return nil, err
+	}
+	if found.Scope != webhook.ScopeConference || found.ConferenceID != int32(conferenceID) {
+		return nil, &dto.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Webhook not found",
+		}
+	}
+
+	return found, nil
+}
+
+// userWebhook finds one of the current user's own webhooks from the ID in
+// the path.
+func userWebhook(service webhook.Service, r *http.Request) (*sqlc.Webhook, error) {
+	found, err := findWebhook(service, r.PathValue("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	session := r.Context().Value("session").(*session.UserSession)
+
+	if found.Scope != webhook.ScopeUser || found.UserID != session.UserID {
+		return nil, &dto.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Webhook not found",
+		}
+	}
+
+	return found, nil
+}
+
+func findWebhook(service webhook.Service, id string) (*sqlc.Webhook, error) {
+	webhookID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, &dto.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Bad webhook ID",
+		}
+	}
+
+	found, err := service.GetWebhook(int32(webhookID))
+	if err != nil {
+		if errors.Is(err, webhook.ErrWebhookNotFound) {
+			return nil, &dto.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Webhook not found",
+			}
+		}
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// deliveryResponse leaves out the response bodies of user webhooks, which
+// may have been recorded before they stopped being kept.
+func deliveryResponse(found *sqlc.Webhook, delivery sqlc.WebhookDelivery) dto.WebhookDeliveryResponse {
+	var response dto.WebhookDeliveryResponse
+	response.Scan(delivery)
+	if found.Scope != webhook.ScopeConference {
+		response.ResponseBody = nil
+	}
+	return response
+}
+
+func webhooksResponse(webhooks []sqlc.Webhook) []dto.WebhookResponse {
+	response := make([]dto.WebhookResponse, len(webhooks))
+	for i, w := range webhooks {
+		response[i].Scan(w)
+	}
+	return response
+}
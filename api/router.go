@@ -2,29 +2,52 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/LMBishop/confplanner/api/handlers"
 	"github.com/LMBishop/confplanner/api/middleware"
+	"github.com/LMBishop/confplanner/pkg/agenda"
 	"github.com/LMBishop/confplanner/pkg/auth"
 	"github.com/LMBishop/confplanner/pkg/calendar"
 	"github.com/LMBishop/confplanner/pkg/conference"
 	"github.com/LMBishop/confplanner/pkg/favourites"
+	"github.com/LMBishop/confplanner/pkg/group"
 	"github.com/LMBishop/confplanner/pkg/ical"
+	"github.com/LMBishop/confplanner/pkg/live"
+	"github.com/LMBishop/confplanner/pkg/mail"
+	"github.com/LMBishop/confplanner/pkg/notification"
+	"github.com/LMBishop/confplanner/pkg/popularity"
+	"github.com/LMBishop/confplanner/pkg/push"
+	"github.com/LMBishop/confplanner/pkg/recommendation"
+	"github.com/LMBishop/confplanner/pkg/search"
 	"github.com/LMBishop/confplanner/pkg/session"
+	"github.com/LMBishop/confplanner/pkg/speaker"
 	"github.com/LMBishop/confplanner/pkg/user"
+	"github.com/LMBishop/confplanner/pkg/webhook"
 )
 
 type ApiServices struct {
-	UserService       user.Service
-	FavouritesService favourites.Service
-	ConferenceService conference.Service
-	CalendarService   calendar.Service
-	IcalService       ical.Service
-	SessionService    session.Service
-	AuthService       auth.Service
+	UserService           user.Service
+	FavouritesService     favourites.Service
+	ConferenceService     conference.Service
+	CalendarService       calendar.Service
+	IcalService           ical.Service
+	SessionService        session.Service
+	AuthService           auth.Service
+	AgendaService         agenda.Service
+	GroupService          group.Service
+	PopularityService     popularity.Service
+	RecommendationService recommendation.Service
+	SearchService         search.Service
+	SpeakerService        speaker.Service
+	NotificationService   notification.Service
+	LiveHub               live.Hub
+	PushService           push.Service
+	MailService           mail.Service
+	WebhookService        webhook.Service
 }
 
-func NewServer(apiServices ApiServices, baseURL string) *http.ServeMux {
+func NewServer(apiServices ApiServices, baseURL string, conflictBuffer time.Duration) *http.ServeMux {
 	mustAuthenticate := middleware.MustAuthenticate(apiServices.UserService, apiServices.SessionService)
 	admin := middleware.MustAuthoriseAdmin(apiServices.UserService, apiServices.SessionService)
 
@@ -35,14 +58,89 @@ func NewServer(apiServices ApiServices, baseURL string) *http.ServeMux {
 	mux.HandleFunc("POST /login/{provider}", handlers.Login(apiServices.AuthService, apiServices.SessionService))
 	mux.HandleFunc("POST /logout", mustAuthenticate(handlers.Logout(apiServices.SessionService)))
 
+	mux.HandleFunc("GET /user/profile", mustAuthenticate(handlers.GetUserProfile(apiServices.UserService)))
+	mux.HandleFunc("PATCH /user/profile", mustAuthenticate(handlers.UpdateUserProfile(apiServices.UserService)))
+	mux.HandleFunc("GET /user/export", mustAuthenticate(handlers.ExportUser(apiServices.UserService, apiServices.FavouritesService, apiServices.ConferenceService, apiServices.CalendarService, apiServices.AgendaService, apiServices.GroupService, apiServices.SpeakerService, apiServices.NotificationService, apiServices.PushService, apiServices.MailService, apiServices.WebhookService, apiServices.SessionService)))
+	mux.HandleFunc("POST /user/email", mustAuthenticate(handlers.RequestEmailVerification(apiServices.MailService)))
+	mux.HandleFunc("DELETE /user/email", mustAuthenticate(handlers.RemoveEmail(apiServices.MailService)))
+	mux.HandleFunc("POST /user/email/verify", handlers.VerifyEmail(apiServices.MailService))
+	mux.HandleFunc("GET /user/webhooks", mustAuthenticate(handlers.GetUserWebhooks(apiServices.WebhookService)))
+	mux.HandleFunc("POST /user/webhooks", mustAuthenticate(handlers.CreateUserWebhook(apiServices.WebhookService, apiServices.ConferenceService)))
+	mux.HandleFunc("DELETE /user/webhooks/{id}", mustAuthenticate(handlers.DeleteUserWebhook(apiServices.WebhookService)))
+	mux.HandleFunc("GET /user/webhooks/{id}/deliveries", mustAuthenticate(handlers.GetUserWebhookDeliveries(apiServices.WebhookService)))
+	mux.HandleFunc("POST /user/webhooks/{id}/deliveries/{deliveryID}/redeliver", mustAuthenticate(handlers.RedeliverUserWebhookDelivery(apiServices.WebhookService)))
+	mux.HandleFunc("GET /user/follows", mustAuthenticate(handlers.GetSpeakerFollows(apiServices.SpeakerService)))
+	mux.HandleFunc("DELETE /user", mustAuthenticate(handlers.DeleteUser(apiServices.UserService, apiServices.SessionService)))
+
 	mux.HandleFunc("GET /conference", mustAuthenticate(handlers.GetConferences(apiServices.ConferenceService)))
 	mux.HandleFunc("GET /conference/{id}", mustAuthenticate(handlers.GetSchedule(apiServices.ConferenceService)))
+	mux.HandleFunc("GET /conference/{id}/events", mustAuthenticate(handlers.GetEvents(apiServices.ConferenceService)))
+	mux.HandleFunc("GET /conference/{id}/popularity", mustAuthenticate(handlers.GetPopularity(apiServices.PopularityService, apiServices.ConferenceService)))
+	mux.HandleFunc("GET /conference/{id}/search", mustAuthenticate(handlers.SearchEvents(apiServices.SearchService, apiServices.ConferenceService)))
+	mux.HandleFunc("GET /conference/{id}/stream", mustAuthenticate(handlers.StreamConference(apiServices.LiveHub, apiServices.ConferenceService)))
+	mux.HandleFunc("GET /conference/{id}/speakers", mustAuthenticate(handlers.GetSpeakers(apiServices.ConferenceService)))
+	mux.HandleFunc("GET /conference/{id}/speakers/{personID}", mustAuthenticate(handlers.GetSpeaker(apiServices.ConferenceService)))
+	mux.HandleFunc("POST /conference/{id}/speakers/{personID}/follow", mustAuthenticate(handlers.FollowSpeaker(apiServices.SpeakerService)))
+	mux.HandleFunc("DELETE /conference/{id}/speakers/{personID}/follow", mustAuthenticate(handlers.UnfollowSpeaker(apiServices.SpeakerService)))
 	mux.HandleFunc("POST /conference", mustAuthenticate(admin(handlers.CreateConference(apiServices.ConferenceService))))
-	mux.HandleFunc("DELETE /conference", mustAuthenticate(admin(handlers.DeleteConference(apiServices.ConferenceService))))
+	mux.HandleFunc("POST /conference/custom", mustAuthenticate(admin(handlers.CreateCustomConference(apiServices.ConferenceService))))
+	mux.HandleFunc("GET /conference/deleted", mustAuthenticate(admin(handlers.GetDeletedConferences(apiServices.ConferenceService))))
+	mux.HandleFunc("GET /conference/{id}/deletion-impact", mustAuthenticate(admin(handlers.GetDeletionImpact(apiServices.ConferenceService))))
+	mux.HandleFunc("DELETE /conference/{id}", mustAuthenticate(admin(handlers.DeleteConference(apiServices.ConferenceService))))
+	mux.HandleFunc("POST /conference/{id}/restore", mustAuthenticate(admin(handlers.RestoreConference(apiServices.ConferenceService))))
+	mux.HandleFunc("PATCH /conference/{id}", mustAuthenticate(admin(handlers.UpdateConference(apiServices.ConferenceService))))
+	mux.HandleFunc("PUT /conference/{id}/visibility", mustAuthenticate(admin(handlers.SetConferenceVisibility(apiServices.ConferenceService))))
+	mux.HandleFunc("GET /conference/{id}/access", mustAuthenticate(admin(handlers.GetConferenceAccess(apiServices.ConferenceService))))
+	mux.HandleFunc("PUT /conference/{id}/access", mustAuthenticate(admin(handlers.SetConferenceAccess(apiServices.ConferenceService))))
+	mux.HandleFunc("GET /conference/{id}/custom-events", mustAuthenticate(handlers.GetCustomEvents(apiServices.ConferenceService)))
+	mux.HandleFunc("POST /conference/{id}/custom-events", mustAuthenticate(handlers.CreateCustomEvent(apiServices.ConferenceService)))
+	mux.HandleFunc("PATCH /conference/{id}/custom-events/{eventID}", mustAuthenticate(handlers.UpdateCustomEvent(apiServices.ConferenceService)))
+	mux.HandleFunc("DELETE /conference/{id}/custom-events/{eventID}", mustAuthenticate(handlers.DeleteCustomEvent(apiServices.ConferenceService)))
+	mux.HandleFunc("GET /conference/{id}/overrides", mustAuthenticate(admin(handlers.GetEventOverrides(apiServices.ConferenceService))))
+	mux.HandleFunc("PUT /conference/{id}/overrides/{guid}", mustAuthenticate(admin(handlers.SetEventOverride(apiServices.ConferenceService))))
+	mux.HandleFunc("DELETE /conference/{id}/overrides/{guid}", mustAuthenticate(admin(handlers.DeleteEventOverride(apiServices.ConferenceService))))
+	mux.HandleFunc("GET /conference/{id}/sources", mustAuthenticate(admin(handlers.GetSources(apiServices.ConferenceService))))
+	mux.HandleFunc("POST /conference/{id}/sources", mustAuthenticate(admin(handlers.AddSource(apiServices.ConferenceService))))
+	mux.HandleFunc("DELETE /conference/{id}/sources/{sourceID}", mustAuthenticate(admin(handlers.DeleteSource(apiServices.ConferenceService))))
+	mux.HandleFunc("GET /conference/{id}/webhooks", mustAuthenticate(admin(handlers.GetConferenceWebhooks(apiServices.WebhookService))))
+	mux.HandleFunc("POST /conference/{id}/webhooks", mustAuthenticate(admin(handlers.CreateConferenceWebhook(apiServices.WebhookService, apiServices.ConferenceService))))
+	mux.HandleFunc("DELETE /conference/{id}/webhooks/{webhookID}", mustAuthenticate(admin(handlers.DeleteConferenceWebhook(apiServices.WebhookService))))
+	mux.HandleFunc("GET /conference/{id}/webhooks/{webhookID}/deliveries", mustAuthenticate(admin(handlers.GetConferenceWebhookDeliveries(apiServices.WebhookService))))
+	mux.HandleFunc("POST /conference/{id}/webhooks/{webhookID}/deliveries/{deliveryID}/redeliver", mustAuthenticate(admin(handlers.RedeliverConferenceWebhookDelivery(apiServices.WebhookService))))
 
 	mux.HandleFunc("GET /favourites/{id}", mustAuthenticate(handlers.GetFavourites(apiServices.FavouritesService)))
 	mux.HandleFunc("POST /favourites", mustAuthenticate(handlers.CreateFavourite(apiServices.FavouritesService)))
 	mux.HandleFunc("DELETE /favourites", mustAuthenticate(handlers.DeleteFavourite(apiServices.FavouritesService)))
+	mux.HandleFunc("PATCH /favourites/{id}", mustAuthenticate(handlers.UpdateFavourite(apiServices.FavouritesService)))
+	mux.HandleFunc("POST /favourites/batch", mustAuthenticate(handlers.CreateFavourites(apiServices.FavouritesService)))
+	mux.HandleFunc("DELETE /favourites/batch", mustAuthenticate(handlers.DeleteFavourites(apiServices.FavouritesService)))
+	mux.HandleFunc("GET /favourites/{id}/export", mustAuthenticate(handlers.ExportFavourites(apiServices.FavouritesService)))
+	mux.HandleFunc("POST /favourites/{id}/import", mustAuthenticate(handlers.ImportFavourites(apiServices.FavouritesService, apiServices.AgendaService)))
+	mux.HandleFunc("GET /favourites/{id}/conflicts", mustAuthenticate(handlers.GetFavouriteConflicts(apiServices.FavouritesService, conflictBuffer)))
+	mux.HandleFunc("GET /favourites/{id}/recommendations", mustAuthenticate(handlers.GetRecommendations(apiServices.RecommendationService)))
+
+	mux.HandleFunc("GET /favourites/{id}/share", mustAuthenticate(handlers.GetAgendaShare(apiServices.AgendaService, baseURL)))
+	mux.HandleFunc("POST /favourites/{id}/share", mustAuthenticate(handlers.CreateAgendaShare(apiServices.AgendaService, baseURL)))
+	mux.HandleFunc("DELETE /favourites/{id}/share", mustAuthenticate(handlers.DeleteAgendaShare(apiServices.AgendaService)))
+	mux.HandleFunc("GET /agenda/{slug}", handlers.GetSharedAgenda(apiServices.AgendaService))
+
+	mux.HandleFunc("GET /groups", mustAuthenticate(handlers.GetGroups(apiServices.GroupService)))
+	mux.HandleFunc("POST /groups", mustAuthenticate(handlers.CreateGroup(apiServices.GroupService)))
+	mux.HandleFunc("GET /groups/invitations", mustAuthenticate(handlers.GetGroupInvitations(apiServices.GroupService)))
+	mux.HandleFunc("DELETE /groups/invitations/{id}", mustAuthenticate(handlers.DeclineGroupInvitation(apiServices.GroupService)))
+	mux.HandleFunc("GET /groups/{id}/members", mustAuthenticate(handlers.GetGroupMembers(apiServices.GroupService)))
+	mux.HandleFunc("POST /groups/{id}/invitations", mustAuthenticate(handlers.InviteGroupMember(apiServices.GroupService)))
+	mux.HandleFunc("POST /groups/{id}/join", mustAuthenticate(handlers.JoinGroup(apiServices.GroupService)))
+	mux.HandleFunc("POST /groups/{id}/leave", mustAuthenticate(handlers.LeaveGroup(apiServices.GroupService)))
+	mux.HandleFunc("GET /groups/{id}/agenda/{conference}", mustAuthenticate(handlers.GetGroupAgenda(apiServices.GroupService)))
+
+	mux.HandleFunc("GET /notifications", mustAuthenticate(handlers.GetNotifications(apiServices.NotificationService)))
+	mux.HandleFunc("POST /notifications/read", mustAuthenticate(handlers.MarkAllNotificationsRead(apiServices.NotificationService)))
+	mux.HandleFunc("POST /notifications/{id}/read", mustAuthenticate(handlers.MarkNotificationRead(apiServices.NotificationService)))
+	mux.HandleFunc("GET /push/key", mustAuthenticate(handlers.GetPushKey(apiServices.PushService)))
+	mux.HandleFunc("POST /push/subscriptions", mustAuthenticate(handlers.CreatePushSubscription(apiServices.PushService)))
+	mux.HandleFunc("DELETE /push/subscriptions", mustAuthenticate(handlers.DeletePushSubscription(apiServices.PushService)))
+	mux.HandleFunc("POST /push/test", mustAuthenticate(handlers.SendTestPush(apiServices.PushService)))
 
 	mux.HandleFunc("GET /calendar", mustAuthenticate(handlers.GetCalendar(apiServices.CalendarService, baseURL)))
 	mux.HandleFunc("POST /calendar", mustAuthenticate(handlers.CreateCalendar(apiServices.CalendarService, baseURL)))
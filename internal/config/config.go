@@ -16,7 +16,38 @@ type Config struct {
 	} `yaml:"database"`
 	Conference struct {
 		ScheduleURL string `yaml:"scheduleURL"`
+		// days deleted conferences are kept for before being purged
+		RetentionDays int `yaml:"retentionDays"`
 	} `yaml:"conference"`
+	Favourites struct {
+		ConflictBufferMinutes    int `yaml:"conflictBufferMinutes"`
+		PopularityThreshold      int `yaml:"popularityThreshold"`
+		PopularityRefreshMinutes int `yaml:"popularityRefreshMinutes"`
+	} `yaml:"favourites"`
+	Push struct {
+		Subject                string `yaml:"subject"`
+		ReminderMinutes        int    `yaml:"reminderMinutes"`
+		AllowInsecureEndpoints bool   `yaml:"allowInsecureEndpoints"`
+	} `yaml:"push"`
+	Mail struct {
+		// smtp, or log to write emails to the log instead of sending them.
+		// log sends through SMTP to a stand-in server started on a loopback
+		// port. To use another local stand-in, such as Mailpit, use smtp
+		// with its host and port and a tls of none.
+		Transport  string `yaml:"transport"`
+		SMTPHost   string `yaml:"smtpHost"`
+		SMTPPort   int    `yaml:"smtpPort"`
+		Username   string `yaml:"username"`
+		Password   string `yaml:"password"`
+		TLS        string `yaml:"tls"`
+		From       string `yaml:"from"`
+		DigestHour int    `yaml:"digestHour"`
+	} `yaml:"mail"`
+	Webhooks struct {
+		// allow plain HTTP endpoints and private, loopback and link-local
+		// addresses, for testing against a local receiver
+		AllowInsecureEndpoints bool `yaml:"allowInsecureEndpoints"`
+	} `yaml:"webhooks"`
 	Auth struct {
 		EnableBasicAuth bool           `yaml:"enableBasicAuth"`
 		AuthProviders   []AuthProvider `yaml:"authProviders"`
@@ -5,17 +5,30 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/LMBishop/confplanner/api"
+	"github.com/LMBishop/confplanner/api/middleware"
 	"github.com/LMBishop/confplanner/internal/config"
+	"github.com/LMBishop/confplanner/pkg/agenda"
 	"github.com/LMBishop/confplanner/pkg/auth"
 	"github.com/LMBishop/confplanner/pkg/calendar"
 	"github.com/LMBishop/confplanner/pkg/conference"
 	"github.com/LMBishop/confplanner/pkg/database"
 	"github.com/LMBishop/confplanner/pkg/favourites"
+	"github.com/LMBishop/confplanner/pkg/group"
 	"github.com/LMBishop/confplanner/pkg/ical"
+	"github.com/LMBishop/confplanner/pkg/live"
+	"github.com/LMBishop/confplanner/pkg/mail"
+	"github.com/LMBishop/confplanner/pkg/notification"
+	"github.com/LMBishop/confplanner/pkg/popularity"
+	"github.com/LMBishop/confplanner/pkg/push"
+	"github.com/LMBishop/confplanner/pkg/recommendation"
+	"github.com/LMBishop/confplanner/pkg/search"
 	"github.com/LMBishop/confplanner/pkg/session"
+	"github.com/LMBishop/confplanner/pkg/speaker"
 	"github.com/LMBishop/confplanner/pkg/user"
+	"github.com/LMBishop/confplanner/pkg/webhook"
 	"github.com/LMBishop/confplanner/web"
 )
 
@@ -43,13 +56,102 @@ func run() error {
 	}
 
 	userService := user.NewService(pool, c.AcceptRegistrations)
-	favouritesService := favourites.NewService(pool)
-	conferenceService, err := conference.NewService(pool)
+	retentionDays := c.Conference.RetentionDays
+	if retentionDays == 0 {
+		retentionDays = 30
+	}
+	conferenceService, err := conference.NewService(pool, time.Duration(retentionDays)*24*time.Hour)
 	if err != nil {
 		return fmt.Errorf("failed to create schedule service: %w", err)
 	}
+	favouritesService := favourites.NewService(pool, conferenceService)
+	conferenceService.AddScheduleListener(func(update conference.ScheduleUpdate) {
+		if update.Deleted {
+			return
+		}
+		if err := favouritesService.ReconcileFavourites(update.ConferenceID, update.Schedule); err != nil {
+			slog.Error("failed to reconcile favourites", "conference", update.ConferenceID, "error", err)
+		}
+	})
+	searchService := search.NewService(conferenceService)
+	conferenceService.AddScheduleListener(searchService.IndexSchedule)
+	notificationService := notification.NewService(pool)
+	speakerService := speaker.NewService(pool, conferenceService, notificationService)
+	conferenceService.AddScheduleListener(func(update conference.ScheduleUpdate) {
+		if err := speakerService.NotifyScheduleChanges(update); err != nil {
+			slog.Error("failed to notify speaker followers", "conference", update.ConferenceID, "error", err)
+		}
+	})
+	liveHub := live.NewHub(conferenceService)
+	conferenceService.AddScheduleListener(func(update conference.ScheduleUpdate) {
+		if update.Deleted {
+			return
+		}
+		liveHub.PublishSchedule(update.ConferenceID, update.Schedule)
+	})
+	favouritesService.AddFavouriteListener(func(change favourites.FavouriteChange) {
+		liveHub.Publish(change.ConferenceID, change.UserID, live.EventFavouriteChanged, live.FavouriteChanged{ConferenceID: change.ConferenceID})
+	})
 	calendarService := calendar.NewService(pool)
-	icalService := ical.NewService(favouritesService, conferenceService)
+	agendaService := agenda.NewService(pool, favouritesService, conferenceService)
+	groupService := group.NewService(pool, conferenceService)
+	popularityService := popularity.NewService(
+		pool,
+		conferenceService,
+		c.Favourites.PopularityThreshold,
+		time.Duration(c.Favourites.PopularityRefreshMinutes)*time.Minute,
+	)
+	conflictBuffer := time.Duration(c.Favourites.ConflictBufferMinutes) * time.Minute
+	icalService := ical.NewService(favouritesService, conferenceService, userService, conflictBuffer)
+	recommendationService := recommendation.NewService(pool, favouritesService, conferenceService, conflictBuffer)
+	pushSubject := c.Push.Subject
+	if pushSubject == "" {
+		pushSubject = c.BaseURL
+	}
+	pushService, err := push.NewService(
+		pool,
+		favouritesService,
+		conferenceService,
+		pushSubject,
+		time.Duration(c.Push.ReminderMinutes)*time.Minute,
+		c.Push.AllowInsecureEndpoints,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create push service: %w", err)
+	}
+	var mailSender mail.Sender
+	switch {
+	case c.Mail.From == "":
+		// email is disabled
+	case c.Mail.Transport == "log":
+		mailSender, err = mail.NewLogSender()
+		if err != nil {
+			return fmt.Errorf("failed to start development SMTP server: %w", err)
+		}
+	case c.Mail.SMTPHost != "":
+		mailSender = mail.NewSMTPSender(c.Mail.SMTPHost, c.Mail.SMTPPort, c.Mail.Username, c.Mail.Password, c.Mail.TLS)
+	}
+	digestHour := c.Mail.DigestHour
+	if digestHour == 0 {
+		digestHour = 7
+	}
+	mailService := mail.NewService(pool, mailSender, userService, favouritesService, conferenceService, c.Mail.From, c.BaseURL, digestHour)
+	conferenceService.AddScheduleListener(func(update conference.ScheduleUpdate) {
+		if err := mailService.NotifyScheduleChanges(update); err != nil {
+			slog.Error("failed to email favourite changes", "conference", update.ConferenceID, "error", err)
+		}
+	})
+	webhookService := webhook.NewService(pool, c.Webhooks.AllowInsecureEndpoints)
+	conferenceService.AddScheduleListener(func(update conference.ScheduleUpdate) {
+		if err := webhookService.PublishScheduleChange(update); err != nil {
+			slog.Error("failed to queue schedule webhooks", "conference", update.ConferenceID, "error", err)
+		}
+	})
+	favouritesService.AddFavouriteListener(func(change favourites.FavouriteChange) {
+		if err := webhookService.PublishFavouriteChange(change); err != nil {
+			slog.Error("failed to queue favourite webhooks", "user", change.UserID, "error", err)
+		}
+	})
 	sessionService := session.NewMemoryStore()
 	authService := auth.NewService()
 
@@ -80,17 +182,28 @@ func run() error {
 
 	mux := http.NewServeMux()
 	api := api.NewServer(api.ApiServices{
-		UserService:       userService,
-		FavouritesService: favouritesService,
-		ConferenceService: conferenceService,
-		CalendarService:   calendarService,
-		IcalService:       icalService,
-		SessionService:    sessionService,
-		AuthService:       authService,
-	}, c.BaseURL)
+		UserService:           userService,
+		FavouritesService:     favouritesService,
+		ConferenceService:     conferenceService,
+		CalendarService:       calendarService,
+		IcalService:           icalService,
+		SessionService:        sessionService,
+		AuthService:           authService,
+		AgendaService:         agendaService,
+		GroupService:          groupService,
+		PopularityService:     popularityService,
+		RecommendationService: recommendationService,
+		SearchService:         searchService,
+		SpeakerService:        speakerService,
+		NotificationService:   notificationService,
+		LiveHub:               liveHub,
+		PushService:           pushService,
+		MailService:           mailService,
+		WebhookService:        webhookService,
+	}, c.BaseURL, conflictBuffer)
 	web := web.NewWebFileServer()
 
-	mux.Handle("/api/", http.StripPrefix("/api", api))
+	mux.Handle("/api/", http.StripPrefix("/api", middleware.Compress(api)))
 	mux.Handle("/", web)
 
 	slog.Info("starting HTTP server", "host", c.Server.Host, "port", c.Server.Port)
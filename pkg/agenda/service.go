@@ -0,0 +1,205 @@
+package agenda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/LMBishop/confplanner/internal/random"
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/LMBishop/confplanner/pkg/favourites"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Service interface {
+	GetShareForUser(id int32, conferenceID int32) (*sqlc.AgendaShare, error)
+	GetSharesForUser(id int32) ([]sqlc.AgendaShare, error)
+	CreateShareForUser(id int32, conferenceID int32, displayName string) (*sqlc.AgendaShare, error)
+	DeleteShareForUser(id int32, conferenceID int32) error
+	GetSharedAgenda(slug string) (*SharedAgenda, error)
+}
+
+// SharedAgenda is the public, read-only view of a user's favourites for a
+// conference.
+type SharedAgenda struct {
+	Share      sqlc.AgendaShare
+	Conference conference.Conference
+	Events     []conference.Event
+}
+
+var (
+	ErrShareNotFound      = errors.New("share not found")
+	ErrConferenceNotFound = errors.New("conference not found")
+	ErrNotShareable       = errors.New("only published, public conferences can be shared")
+)
+
+type service struct {
+	pool              *pgxpool.Pool
+	favouritesService favourites.Service
+	conferenceService conference.Service
+}
+
+func NewService(pool *pgxpool.Pool, favouritesService favourites.Service, conferenceService conference.Service) Service {
+	return &service{
+		pool:              pool,
+		favouritesService: favouritesService,
+		conferenceService: conferenceService,
+	}
+}
+
+func (s *service) GetShareForUser(id int32, conferenceID int32) (*sqlc.AgendaShare, error) {
+	queries := sqlc.New(s.pool)
+
+	share, err := queries.GetAgendaShareForUserConference(context.Background(), sqlc.GetAgendaShareForUserConferenceParams{
+		UserID:       id,
+		ConferenceID: conferenceID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrShareNotFound
+		}
+		return nil, fmt.Errorf("could not fetch share: %w", err)
+	}
+
+	return &share, nil
+}
+
+func (s *service) GetSharesForUser(id int32) ([]sqlc.AgendaShare, error) {
+	queries := sqlc.New(s.pool)
+
+	shares, err := queries.GetAgendaSharesForUser(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch shares: %w", err)
+	}
+
+	return shares, nil
+}
+
+func (s *service) CreateShareForUser(id int32, conferenceID int32, displayName string) (*sqlc.AgendaShare, error) {
+	ok, err := s.conferenceService.CanAccess(conferenceID, id)
+	if err != nil {
+		if errors.Is(err, conference.ErrConferenceNotFound) {
+			return nil, ErrConferenceNotFound
+		}
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrConferenceNotFound
+	}
+
+	details, err := s.conferenceService.GetConference(conferenceID)
+	if err != nil {
+		return nil, err
+	}
+	if !shareable(*details) {
+		return nil, ErrNotShareable
+	}
+
+	queries := sqlc.New(s.pool)
+
+	slug, err := random.String(16)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate random string: %w", err)
+	}
+
+	share, err := queries.CreateAgendaShare(context.Background(), sqlc.CreateAgendaShareParams{
+		UserID:       id,
+		ConferenceID: conferenceID,
+		Slug:         slug,
+		DisplayName: pgtype.Text{
+			String: displayName,
+			Valid:  displayName != "",
+		},
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, ErrConferenceNotFound
+		}
+		return nil, fmt.Errorf("could not create share: %w", err)
+	}
+
+	return &share, nil
+}
+
+func (s *service) DeleteShareForUser(id int32, conferenceID int32) error {
+	queries := sqlc.New(s.pool)
+
+	rowsAffected, err := queries.DeleteAgendaShareForUserConference(context.Background(), sqlc.DeleteAgendaShareForUserConferenceParams{
+		UserID:       id,
+		ConferenceID: conferenceID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete share: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrShareNotFound
+	}
+
+	return nil
+}
+
+func (s *service) GetSharedAgenda(slug string) (*SharedAgenda, error) {
+	queries := sqlc.New(s.pool)
+
+	share, err := queries.GetAgendaShareBySlug(context.Background(), slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrShareNotFound
+		}
+		return nil, fmt.Errorf("could not fetch share: %w", err)
+	}
+
+	// shared agendas are public, so stop showing them if the conference
+	// no longer is
+	details, err := s.conferenceService.GetConference(share.ConferenceID)
+	if err != nil {
+		return nil, err
+	}
+	if !shareable(*details) {
+		return nil, ErrShareNotFound
+	}
+
+	schedule, _, err := s.conferenceService.GetSchedule(share.ConferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	favouriteEvents, err := s.favouritesService.GetFavouriteEventsForUserConference(share.UserID, share.ConferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]conference.Event, 0, len(favouriteEvents))
+	for _, favouriteEvent := range favouriteEvents {
+		event := favouriteEvent.Event
+		if event.Custom != nil {
+			if !event.Custom.Shared {
+				// personal events are only visible to their owner
+				continue
+			}
+			event.Custom = &conference.Custom{Shared: true}
+		}
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+
+	return &SharedAgenda{
+		Share:      share,
+		Conference: schedule.Conference,
+		Events:     events,
+	}, nil
+}
+
+// shareable reports whether anyone may be shown a conference through a
+// public link, which is only the case for published, public conferences.
+func shareable(details sqlc.Conference) bool {
+	return details.Visibility == conference.VisibilityPublished && details.Access == conference.AccessPublic
+}
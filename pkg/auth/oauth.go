@@ -167,6 +167,10 @@ func (p *OIDCAuthProvider) CompleteJourney(ctx context.Context, authCode string,
 		}
 	}
 
+	if err := p.userService.SaveOIDCClaims(u.ID, claims); err != nil {
+		return nil, errors.Join(ErrUserSyncFailed, err)
+	}
+
 	return u, nil
 }
 
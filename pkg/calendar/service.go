@@ -2,11 +2,10 @@ package calendar
 
 import (
 	"context"
-	"crypto/rand"
 	"errors"
 	"fmt"
-	"math/big"
 
+	"github.com/LMBishop/confplanner/internal/random"
 	"github.com/LMBishop/confplanner/pkg/database/sqlc"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -64,12 +63,12 @@ func (s *service) GetCalendarByName(name string) (*sqlc.Calendar, error) {
 func (s *service) CreateCalendarForUser(id int32) (*sqlc.Calendar, error) {
 	queries := sqlc.New(s.pool)
 
-	name, err := randomString(16)
+	name, err := random.String(16)
 	if err != nil {
 		return nil, fmt.Errorf("could not generate random string: %w", err)
 	}
 
-	key, err := randomString(32)
+	key, err := random.String(32)
 	if err != nil {
 		return nil, fmt.Errorf("could not generate random string: %w", err)
 	}
@@ -96,17 +95,3 @@ func (s *service) DeleteCalendarForUser(id int32) error {
 
 	return nil
 }
-
-func randomString(n int) (string, error) {
-	const letters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
-	ret := make([]byte, n)
-	for i := 0; i < n; i++ {
-		num, err := rand.Int(rand.Reader, big.NewInt(int64(len(letters))))
-		if err != nil {
-			return "", err
-		}
-		ret[i] = letters[num.Int64()]
-	}
-
-	return string(ret), nil
-}
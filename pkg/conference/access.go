@@ -0,0 +1,213 @@
+package conference
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/tidwall/gjson"
+)
+
+// Public conferences can be seen by everyone. Restricted conferences can
+// only be seen by the users and groups they are granted to, and claim
+// conferences by users whose OIDC claims match. Admins can see them all.
+const (
+	AccessPublic     = "public"
+	AccessRestricted = "restricted"
+	AccessClaim      = "claim"
+)
+
+var AccessModes = []string{AccessPublic, AccessRestricted, AccessClaim}
+
+// Access describes who can see a conference. Users and groups are granted
+// access whatever the mode, but only count while it is restricted.
+type Access struct {
+	Mode     string
+	UserIDs  []int32
+	GroupIDs []int32
+	// a gjson path into the user's OIDC claims, which must hold one of
+	// ClaimValues, as with the login filter
+	Claim       string
+	ClaimValues []string
+}
+
+var ErrInvalidAccess = errors.New("invalid access")
+
+func (s *service) GetAccess(conferenceID int32) (*Access, error) {
+	details, err := s.GetConference(conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := sqlc.New(s.pool)
+
+	grants, err := queries.GetConferenceAccessGrants(context.Background(), conferenceID)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch access grants: %w", err)
+	}
+
+	access := &Access{
+		Mode:        details.Access,
+		UserIDs:     []int32{},
+		GroupIDs:    []int32{},
+		Claim:       details.AccessClaim.String,
+		ClaimValues: details.AccessClaimValues,
+	}
+	for _, grant := range grants {
+		if grant.UserID.Valid {
+			access.UserIDs = append(access.UserIDs, grant.UserID.Int32)
+		} else {
+			access.GroupIDs = append(access.GroupIDs, grant.GroupID.Int32)
+		}
+	}
+	return access, nil
+}
+
+// SetAccess changes who can see a conference, replacing its grants.
+func (s *service) SetAccess(conferenceID int32, access Access) (*Access, error) {
+	if !slices.Contains(AccessModes, access.Mode) {
+		return nil, ErrInvalidAccess
+	}
+
+	params := sqlc.SetConferenceAccessParams{
+		ID:                conferenceID,
+		Access:            access.Mode,
+		AccessClaimValues: []string{},
+	}
+	if access.Mode == AccessClaim {
+		if access.Claim == "" || len(access.ClaimValues) == 0 {
+			return nil, ErrInvalidAccess
+		}
+		params.AccessClaim = pgtype.Text{String: access.Claim, Valid: true}
+		params.AccessClaimValues = access.ClaimValues
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	c, ok := s.conferences[conferenceID]
+	if !ok {
+		return nil, ErrConferenceNotFound
+	}
+
+	ctx := context.Background()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	queries := sqlc.New(s.pool).WithTx(tx)
+
+	conference, err := queries.SetConferenceAccess(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrConferenceNotFound
+		}
+		return nil, fmt.Errorf("could not update conference: %w", err)
+	}
+
+	if err := queries.DeleteConferenceAccessGrants(ctx, conferenceID); err != nil {
+		return nil, fmt.Errorf("could not delete access grants: %w", err)
+	}
+
+	grants := make([]sqlc.CreateConferenceAccessGrantParams, 0, len(access.UserIDs)+len(access.GroupIDs))
+	for _, userID := range slices.Compact(slices.Sorted(slices.Values(access.UserIDs))) {
+		grants = append(grants, sqlc.CreateConferenceAccessGrantParams{
+			ConferenceID: conferenceID,
+			UserID:       pgtype.Int4{Int32: userID, Valid: true},
+		})
+	}
+	for _, groupID := range slices.Compact(slices.Sorted(slices.Values(access.GroupIDs))) {
+		grants = append(grants, sqlc.CreateConferenceAccessGrantParams{
+			ConferenceID: conferenceID,
+			GroupID:      pgtype.Int4{Int32: groupID, Valid: true},
+		})
+	}
+	for _, grant := range grants {
+		if err := queries.CreateConferenceAccessGrant(ctx, grant); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return nil, errors.Join(ErrInvalidAccess, errors.New("no such user or group"))
+			}
+			return nil, fmt.Errorf("could not grant access: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	c.lock.Lock()
+	c.details = conference
+	c.lock.Unlock()
+
+	return s.GetAccess(conferenceID)
+}
+
+// CanAccess reports whether a user can see a conference. Drafts can only
+// be seen by admins.
+func (s *service) CanAccess(conferenceID int32, userID int32) (bool, error) {
+	details, err := s.GetConference(conferenceID)
+	if err != nil {
+		return false, err
+	}
+
+	return s.canAccess(*details, userID)
+}
+
+func (s *service) canAccess(conference sqlc.Conference, userID int32) (bool, error) {
+	if conference.Access == AccessPublic && conference.Visibility != VisibilityDraft {
+		return true, nil
+	}
+
+	queries := sqlc.New(s.pool)
+
+	access, err := queries.GetConferenceAccessForUser(context.Background(), sqlc.GetConferenceAccessForUserParams{
+		ConferenceID: conference.ID,
+		ID:           userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("could not fetch access: %w", err)
+	}
+
+	switch {
+	case access.Admin:
+		return true, nil
+	case conference.Visibility == VisibilityDraft:
+		return false, nil
+	case conference.Access == AccessRestricted:
+		return access.Granted, nil
+	case conference.Access == AccessClaim:
+		return claimMatches(access.Claims, conference.AccessClaim.String, conference.AccessClaimValues), nil
+	}
+	return true, nil
+}
+
+// claimMatches reports whether the claim at path holds one of the allowed
+// values. Users who have never logged in through OIDC have no claims.
+func claimMatches(claims []byte, path string, allowed []string) bool {
+	if len(claims) == 0 {
+		return false
+	}
+
+	claim := gjson.GetBytes(claims, path)
+	if !claim.Exists() {
+		return false
+	}
+	for _, value := range claim.Array() {
+		if slices.Contains(allowed, value.String()) {
+			return true
+		}
+	}
+	return false
+}
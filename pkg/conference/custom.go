@@ -0,0 +1,441 @@
+package conference
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CustomConference describes a conference without a source schedule, made
+// up entirely of custom events. Start and End are the first and last days.
+type CustomConference struct {
+	Title    string
+	Venue    string
+	City     string
+	TimeZone string
+	Start    time.Time
+	End      time.Time
+}
+
+// CustomEventDetails describes an event authored locally rather than
+// fetched from the conference's source. Shared events are part of the
+// schedule everyone sees, while personal events are only seen by the user
+// who created them.
+type CustomEventDetails struct {
+	Title    string
+	Abstract string
+	Room     string
+	URL      string
+	Start    time.Time
+	End      time.Time
+	Shared   bool
+}
+
+// Custom marks an event as authored locally. Custom events are given
+// negative IDs so they never collide with events from the source.
+type Custom struct {
+	Shared  bool  `json:"shared"`
+	OwnerID int32 `json:"ownerId,omitempty"`
+}
+
+var (
+	ErrCustomEventNotFound  = errors.New("custom event not found")
+	ErrInvalidCustomEvent   = errors.New("invalid custom event")
+	ErrInvalidConferenceTZ  = errors.New("unknown time zone")
+	ErrInvalidConferenceEnd = errors.New("conference must end on or after the day it starts")
+)
+
+func (s *service) CreateCustomConference(details CustomConference) (*sqlc.Conference, error) {
+	if _, err := time.LoadLocation(details.TimeZone); err != nil || details.TimeZone == "" {
+		return nil, ErrInvalidConferenceTZ
+	}
+	if details.End.Before(details.Start) {
+		return nil, ErrInvalidConferenceEnd
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	queries := sqlc.New(s.pool)
+
+	conference, err := queries.CreateCustomConference(context.Background(), sqlc.CreateCustomConferenceParams{
+		Title:    pgtype.Text{String: details.Title, Valid: true},
+		Venue:    pgtype.Text{String: details.Venue, Valid: details.Venue != ""},
+		City:     pgtype.Text{String: details.City, Valid: details.City != ""},
+		TimeZone: pgtype.Text{String: details.TimeZone, Valid: true},
+		StartsOn: pgtype.Date{Time: details.Start, Valid: true},
+		EndsOn:   pgtype.Date{Time: details.End, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create conference: %w", err)
+	}
+
+	c := &loadedConference{
+		details:     conference,
+		overrides:   make(map[string]sqlc.EventOverride),
+		lastUpdated: time.Unix(0, 0),
+	}
+	if _, err := c.updateSchedule(); err != nil {
+		return nil, err
+	}
+
+	s.conferences[conference.ID] = c
+	s.notifyListeners(conference.ID, c.schedule)
+
+	return &conference, nil
+}
+
+// GetScheduleForUser returns the conference's schedule with the user's
+// personal events added to it.
+func (s *service) GetScheduleForUser(id int32, userID int32) (*Schedule, time.Time, error) {
+	schedule, lastUpdated, err := s.GetSchedule(id)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	queries := sqlc.New(s.pool)
+
+	personal, err := queries.GetPersonalCustomEvents(context.Background(), sqlc.GetPersonalCustomEventsParams{
+		ConferenceID: id,
+		OwnerID:      pgtype.Int4{Int32: userID, Valid: true},
+	})
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("could not fetch personal events: %w", err)
+	}
+	if len(personal) == 0 {
+		return schedule, lastUpdated, nil
+	}
+
+	withPersonal := cloneSchedule(schedule, true)
+	location := schedule.location()
+	for _, custom := range personal {
+		withPersonal.placeEvent(customEvent(custom, location), nil)
+	}
+	withPersonal.sortEvents()
+
+	// the checksum has to change whenever the personal events do
+	data, err := json.Marshal(personal)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to hash schedule: %w", err)
+	}
+	checksum := sha256.Sum256(append([]byte(schedule.Checksum), data...))
+	withPersonal.Checksum = hex.EncodeToString(checksum[:])
+
+	return withPersonal, lastUpdated, nil
+}
+
+// GetCustomEvents returns the conference's shared custom events along with
+// the user's personal ones.
+func (s *service) GetCustomEvents(conferenceID int32, userID int32) ([]sqlc.CustomEvent, error) {
+	if !s.hasConference(conferenceID) {
+		return nil, ErrConferenceNotFound
+	}
+
+	queries := sqlc.New(s.pool)
+
+	events, err := queries.GetCustomEventsForUser(context.Background(), sqlc.GetCustomEventsForUserParams{
+		ConferenceID: conferenceID,
+		OwnerID:      pgtype.Int4{Int32: userID, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch custom events: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetCustomEventsOwnedByUser returns every custom event a user has
+// authored, across all conferences.
+func (s *service) GetCustomEventsOwnedByUser(userID int32) ([]sqlc.CustomEvent, error) {
+	queries := sqlc.New(s.pool)
+
+	events, err := queries.GetCustomEventsOwnedByUser(context.Background(), pgtype.Int4{Int32: userID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch custom events: %w", err)
+	}
+
+	return events, nil
+}
+
+func (s *service) GetCustomEvent(conferenceID int32, id int32) (*sqlc.CustomEvent, error) {
+	queries := sqlc.New(s.pool)
+
+	event, err := queries.GetCustomEvent(context.Background(), sqlc.GetCustomEventParams{
+		ID:           id,
+		ConferenceID: conferenceID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCustomEventNotFound
+		}
+		return nil, fmt.Errorf("could not fetch custom event: %w", err)
+	}
+
+	return &event, nil
+}
+
+func (s *service) CreateCustomEvent(conferenceID int32, userID int32, details CustomEventDetails) (*sqlc.CustomEvent, error) {
+	if err := details.validate(); err != nil {
+		return nil, err
+	}
+	if !s.hasConference(conferenceID) {
+		return nil, ErrConferenceNotFound
+	}
+
+	queries := sqlc.New(s.pool)
+
+	event, err := queries.CreateCustomEvent(context.Background(), sqlc.CreateCustomEventParams{
+		ConferenceID: conferenceID,
+		OwnerID:      pgtype.Int4{Int32: userID, Valid: true},
+		Shared:       details.Shared,
+		Title:        details.Title,
+		Abstract:     details.Abstract,
+		Room:         details.Room,
+		Url:          details.URL,
+		StartTime:    pgtype.Timestamptz{Time: details.Start, Valid: true},
+		EndTime:      pgtype.Timestamptz{Time: details.End, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create custom event: %w", err)
+	}
+
+	if event.Shared {
+		if err := s.reloadCustomEvents(conferenceID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &event, nil
+}
+
+// UpdateCustomEvent replaces the details of a custom event. Whether it is
+// shared can't be changed.
+func (s *service) UpdateCustomEvent(conferenceID int32, id int32, details CustomEventDetails) (*sqlc.CustomEvent, error) {
+	if err := details.validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.GetCustomEvent(conferenceID, id); err != nil {
+		return nil, err
+	}
+
+	queries := sqlc.New(s.pool)
+
+	event, err := queries.UpdateCustomEvent(context.Background(), sqlc.UpdateCustomEventParams{
+		ID:        id,
+		Title:     details.Title,
+		Abstract:  details.Abstract,
+		Room:      details.Room,
+		Url:       details.URL,
+		StartTime: pgtype.Timestamptz{Time: details.Start, Valid: true},
+		EndTime:   pgtype.Timestamptz{Time: details.End, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCustomEventNotFound
+		}
+		return nil, fmt.Errorf("could not update custom event: %w", err)
+	}
+
+	if event.Shared {
+		if err := s.reloadCustomEvents(conferenceID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &event, nil
+}
+
+func (s *service) DeleteCustomEvent(conferenceID int32, id int32) error {
+	event, err := s.GetCustomEvent(conferenceID, id)
+	if err != nil {
+		return err
+	}
+
+	queries := sqlc.New(s.pool)
+
+	if err := queries.DeleteCustomEvent(context.Background(), id); err != nil {
+		return fmt.Errorf("could not delete custom event: %w", err)
+	}
+
+	if event.Shared {
+		return s.reloadCustomEvents(conferenceID)
+	}
+	return nil
+}
+
+func (s *service) hasConference(id int32) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	_, ok := s.conferences[id]
+	return ok
+}
+
+// reloadCustomEvents fetches a conference's shared custom events and
+// rebuilds its schedule with them.
+func (s *service) reloadCustomEvents(conferenceID int32) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	c, ok := s.conferences[conferenceID]
+	if !ok {
+		return ErrConferenceNotFound
+	}
+
+	queries := sqlc.New(s.pool)
+
+	events, err := queries.GetSharedCustomEvents(context.Background(), conferenceID)
+	if err != nil {
+		return fmt.Errorf("could not fetch custom events: %w", err)
+	}
+
+	c.lock.Lock()
+	c.customEvents = events
+	err = c.rebuildSchedule()
+	schedule := c.schedule
+	c.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if schedule != nil {
+		s.notifyListeners(conferenceID, schedule)
+	}
+
+	return nil
+}
+
+// lookupPersonalEvent finds one of a user's personal custom events by its
+// GUID, for favourites and calendars which refer to events outside the
+// shared schedule. Other users' personal events are never found.
+func (s *service) lookupPersonalEvent(conferenceID int32, guid string, userID int32, location *time.Location) (*Event, error) {
+	if userID == 0 {
+		return nil, ErrEventNotFound
+	}
+
+	var eventGUID pgtype.UUID
+	if err := eventGUID.Scan(guid); err != nil {
+		return nil, ErrEventNotFound
+	}
+
+	queries := sqlc.New(s.pool)
+
+	custom, err := queries.GetCustomEventByGuid(context.Background(), sqlc.GetCustomEventByGuidParams{
+		Guid:         eventGUID,
+		ConferenceID: conferenceID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrEventNotFound
+		}
+		return nil, fmt.Errorf("could not fetch custom event: %w", err)
+	}
+	if custom.Shared || custom.OwnerID.Int32 != userID {
+		return nil, ErrEventNotFound
+	}
+
+	event := customEvent(custom, location)
+	return &event, nil
+}
+
+func (d CustomEventDetails) validate() error {
+	if strings.TrimSpace(d.Title) == "" {
+		return fmt.Errorf("%w: a title is required", ErrInvalidCustomEvent)
+	}
+	if !d.End.After(d.Start) {
+		return fmt.Errorf("%w: event must end after it starts", ErrInvalidCustomEvent)
+	}
+	return nil
+}
+
+func customEvent(custom sqlc.CustomEvent, location *time.Location) Event {
+	start := custom.StartTime.Time.In(location)
+	end := custom.EndTime.Time.In(location)
+
+	// abstracts from the source are HTML, so the plain text of a custom
+	// event's abstract is escaped to match
+	var abstract string
+	if custom.Abstract != "" {
+		abstract = "<p>" + strings.ReplaceAll(html.EscapeString(custom.Abstract), "\n", "<br>") + "</p>"
+	}
+
+	return Event{
+		ID:          -custom.ID,
+		GUID:        custom.Guid.String(),
+		Date:        start.Format(time.RFC3339),
+		Start:       start,
+		End:         end,
+		Duration:    int32(end.Sub(start).Minutes()),
+		Room:        custom.Room,
+		URL:         custom.Url,
+		Title:       custom.Title,
+		Abstract:    abstract,
+		Persons:     make([]Person, 0),
+		Attachments: make([]Attachment, 0),
+		Links:       make([]Link, 0),
+		Custom: &Custom{
+			Shared:  custom.Shared,
+			OwnerID: custom.OwnerID.Int32,
+		},
+	}
+}
+
+// location is the time zone the conference takes place in.
+func (s *Schedule) location() *time.Location {
+	if location, err := time.LoadLocation(s.Conference.TimeZoneName); err == nil && s.Conference.TimeZoneName != "" {
+		return location
+	}
+	if len(s.Days) > 0 {
+		return s.Days[0].Start.Location()
+	}
+	return time.UTC
+}
+
+// customSchedule makes an empty schedule for a conference without a
+// source, with a day for each of its dates.
+func customSchedule(conference sqlc.Conference) (*Schedule, error) {
+	location, err := time.LoadLocation(conference.TimeZone.String)
+	if err != nil {
+		return nil, err
+	}
+
+	first := conference.StartsOn.Time
+	last := conference.EndsOn.Time
+
+	schedule := &Schedule{
+		Conference: Conference{
+			Title:            conference.Title.String,
+			Venue:            conference.Venue.String,
+			City:             conference.City.String,
+			Start:            first.Format(time.DateOnly),
+			End:              last.Format(time.DateOnly),
+			DayChange:        "00:00:00",
+			TimeslotDuration: "00:15:00",
+			TimeZoneName:     location.String(),
+		},
+		Tracks: make([]Track, 0),
+		Days:   make([]Day, 0),
+	}
+	for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+		start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location)
+		schedule.Days = append(schedule.Days, Day{
+			Date:  date.Format(time.DateOnly),
+			Start: start,
+			End:   start.AddDate(0, 0, 1),
+			Rooms: make([]Room, 0),
+		})
+	}
+	schedule.Conference.Days = len(schedule.Days)
+
+	return schedule, nil
+}
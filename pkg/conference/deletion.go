@@ -0,0 +1,128 @@
+package conference
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DeletionImpact is what would be lost if a conference were purged.
+type DeletionImpact struct {
+	Users      int64
+	Favourites int64
+}
+
+func (s *service) Retention() time.Duration {
+	return s.retention
+}
+
+func (s *service) GetDeletionImpact(id int32) (*DeletionImpact, error) {
+	queries := sqlc.New(s.pool)
+
+	impact, err := queries.GetConferenceFavouriteImpact(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("could not count favourites: %w", err)
+	}
+
+	return &DeletionImpact{
+		Users:      impact.Users,
+		Favourites: impact.Favourites,
+	}, nil
+}
+
+// DeleteConference hides a conference and stops fetching its schedule. It
+// and everyone's favourites are kept until the retention window has passed,
+// during which it can be restored.
+func (s *service) DeleteConference(id int32) (*sqlc.Conference, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	queries := sqlc.New(s.pool)
+
+	conference, err := queries.SoftDeleteConference(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrConferenceNotFound
+		}
+		return nil, fmt.Errorf("could not delete conference: %w", err)
+	}
+
+	delete(s.conferences, id)
+	s.notifyDeleted(id)
+	return &conference, nil
+}
+
+func (s *service) GetDeletedConferences() ([]sqlc.Conference, error) {
+	queries := sqlc.New(s.pool)
+
+	conferences, err := queries.GetDeletedConferences(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch deleted conferences: %w", err)
+	}
+
+	return conferences, nil
+}
+
+// RestoreConference brings back a deleted conference whose retention
+// window hasn't passed yet.
+func (s *service) RestoreConference(id int32) (*sqlc.Conference, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	queries := sqlc.New(s.pool)
+
+	conference, err := queries.RestoreConference(context.Background(), sqlc.RestoreConferenceParams{
+		ID:        id,
+		DeletedAt: pgtype.Timestamptz{Time: time.Now().Add(-s.retention), Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrConferenceNotFound
+		}
+		return nil, fmt.Errorf("could not restore conference: %w", err)
+	}
+
+	c, err := loadConference(queries, conference)
+	if err != nil {
+		return nil, err
+	}
+	s.conferences[id] = c
+
+	return &conference, nil
+}
+
+func (s *service) runPurge() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.purgeDeletedConferences(); err != nil {
+			slog.Error("failed to purge deleted conferences", "error", err)
+		}
+	}
+}
+
+// purgeDeletedConferences permanently deletes conferences whose retention
+// window has passed, along with their favourites.
+func (s *service) purgeDeletedConferences() error {
+	queries := sqlc.New(s.pool)
+
+	purged, err := queries.PurgeDeletedConferences(context.Background(), pgtype.Timestamptz{
+		Time:  time.Now().Add(-s.retention),
+		Valid: true,
+	})
+	if err != nil {
+		return fmt.Errorf("could not purge conferences: %w", err)
+	}
+
+	if len(purged) > 0 {
+		slog.Info("purged deleted conferences", "conferences", purged)
+	}
+	return nil
+}
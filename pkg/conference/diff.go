@@ -0,0 +1,86 @@
+package conference
+
+import (
+	"slices"
+	"sort"
+)
+
+// EventChanges describes how the events in a schedule differ from an
+// earlier version of it.
+type EventChanges struct {
+	Added   []Event
+	Changed []EventChange
+	Removed []Event
+}
+
+// EventChange is an event which was moved, retimed, retitled or given
+// different speakers.
+type EventChange struct {
+	Before Event
+	After  Event
+}
+
+func (c EventChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Changed) == 0 && len(c.Removed) == 0
+}
+
+// DiffEvents compares the events in two versions of a schedule. Events are
+// matched by GUID, or by ID where the source does not give GUIDs, and are
+// returned in order of their start time. Events an admin has cancelled
+// count as removed.
+func DiffEvents(before *Schedule, after *Schedule) EventChanges {
+	previous := eventsByKey(before)
+	current := eventsByKey(after)
+
+	var changes EventChanges
+	for key, event := range current {
+		old, ok := previous[key]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, event)
+		case old.Title != event.Title || !old.Start.Equal(event.Start) || !old.End.Equal(event.End) || old.Room != event.Room || !samePersons(old.Persons, event.Persons):
+			changes.Changed = append(changes.Changed, EventChange{Before: old, After: event})
+		}
+	}
+	for key, event := range previous {
+		if _, ok := current[key]; !ok {
+			changes.Removed = append(changes.Removed, event)
+		}
+	}
+
+	sort.Slice(changes.Added, func(i, j int) bool {
+		return changes.Added[i].Start.Before(changes.Added[j].Start)
+	})
+	sort.Slice(changes.Changed, func(i, j int) bool {
+		return changes.Changed[i].After.Start.Before(changes.Changed[j].After.Start)
+	})
+	sort.Slice(changes.Removed, func(i, j int) bool {
+		return changes.Removed[i].Start.Before(changes.Removed[j].Start)
+	})
+
+	return changes
+}
+
+func samePersons(a []Person, b []Person) bool {
+	return slices.EqualFunc(a, b, func(a Person, b Person) bool {
+		return a.ID == b.ID && a.Name == b.Name
+	})
+}
+
+func eventsByKey(schedule *Schedule) map[string]Event {
+	events := make(map[string]Event)
+	if schedule == nil {
+		return events
+	}
+	for _, day := range schedule.Days {
+		for _, room := range day.Rooms {
+			for _, event := range room.Events {
+				if event.Cancelled() {
+					continue
+				}
+				events[event.Key()] = event
+			}
+		}
+	}
+	return events
+}
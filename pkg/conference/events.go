@@ -0,0 +1,183 @@
+package conference
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EventFilter narrows down the events returned by GetEvents. Zero values
+// are ignored, and multiple values for the same field match any of them.
+type EventFilter struct {
+	Days    []string
+	Rooms   []string
+	Tracks  []string
+	Types   []string
+	Speaker string
+	// events overlapping the range [From, To)
+	From time.Time
+	To   time.Time
+	// events in progress at the time of the request
+	HappeningNow bool
+	// events starting between now and now+StartingWithin
+	StartingWithin time.Duration
+	// include the personal custom events of this user
+	UserID int32
+}
+
+// EventPage is a page of events ordered by start time. NextCursor is empty
+// if there are no more events.
+type EventPage struct {
+	Events      []Event
+	NextCursor  string
+	LastUpdated time.Time
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+func (s *service) GetEvents(conferenceID int32, filter EventFilter, cursor string, limit int) (*EventPage, error) {
+	schedule, lastUpdated, err := s.GetScheduleForUser(conferenceID, filter.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var after *eventCursor
+	if cursor != "" {
+		after, err = decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	events := make([]Event, 0)
+	for _, day := range schedule.Days {
+		if len(filter.Days) > 0 && !containsFold(filter.Days, day.Date) {
+			continue
+		}
+		for _, room := range day.Rooms {
+			for _, event := range room.Events {
+				if filter.matches(event, now) {
+					events = append(events, event)
+				}
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return compareEvents(events[i], events[j]) < 0
+	})
+
+	start := 0
+	if after != nil {
+		start = sort.Search(len(events), func(i int) bool {
+			return compareEvents(events[i], Event{ID: after.id, Start: after.start}) > 0
+		})
+	}
+	events = events[start:]
+
+	page := &EventPage{
+		LastUpdated: lastUpdated,
+	}
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+		page.NextCursor = encodeCursor(events[limit-1])
+	}
+	page.Events = events
+
+	return page, nil
+}
+
+func (f EventFilter) matches(event Event, now time.Time) bool {
+	if len(f.Rooms) > 0 && !containsFold(f.Rooms, event.Room) {
+		return false
+	}
+	if len(f.Tracks) > 0 && !containsFold(f.Tracks, event.Track) {
+		return false
+	}
+	if len(f.Types) > 0 && !containsFold(f.Types, event.Type) {
+		return false
+	}
+	if f.Speaker != "" {
+		found := false
+		for _, person := range event.Persons {
+			if strings.Contains(strings.ToLower(person.Name), strings.ToLower(f.Speaker)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !f.From.IsZero() && !event.End.After(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !event.Start.Before(f.To) {
+		return false
+	}
+	// a cancelled event isn't happening or about to start
+	if (f.HappeningNow || f.StartingWithin > 0) && event.Cancelled() {
+		return false
+	}
+	if f.HappeningNow && (now.Before(event.Start) || !now.Before(event.End)) {
+		return false
+	}
+	if f.StartingWithin > 0 && (event.Start.Before(now) || event.Start.After(now.Add(f.StartingWithin))) {
+		return false
+	}
+	return true
+}
+
+func containsFold(values []string, s string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// events are ordered by start time, with the ID breaking ties so that the
+// order is stable across requests
+func compareEvents(a Event, b Event) int {
+	if c := a.Start.Compare(b.Start); c != 0 {
+		return c
+	}
+	if a.ID < b.ID {
+		return -1
+	} else if a.ID > b.ID {
+		return 1
+	}
+	return 0
+}
+
+type eventCursor struct {
+	start time.Time
+	id    int32
+}
+
+func encodeCursor(event Event) string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%d:%d", event.Start.UnixNano(), event.ID))
+}
+
+func decodeCursor(cursor string) (*eventCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var start int64
+	var id int32
+	if _, err := fmt.Sscanf(string(decoded), "%d:%d", &start, &id); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &eventCursor{
+		start: time.Unix(0, start),
+		id:    id,
+	}, nil
+}
@@ -0,0 +1,221 @@
+package conference
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Drafts are only visible to admins. Archived conferences are no longer
+// refetched, and are served from a snapshot of their schedule taken when
+// they were archived.
+const (
+	VisibilityDraft     = "draft"
+	VisibilityPublished = "published"
+	VisibilityArchived  = "archived"
+)
+
+var Visibilities = []string{VisibilityDraft, VisibilityPublished, VisibilityArchived}
+
+// When a conference takes place relative to today, going by the dates
+// derived from its schedule.
+const (
+	WhenUpcoming = "upcoming"
+	WhenOngoing  = "ongoing"
+	WhenPast     = "past"
+)
+
+// ConferenceFilter narrows down the conferences returned by GetConferences.
+// Zero values are ignored.
+type ConferenceFilter struct {
+	Visibilities []string
+	// one of WhenUpcoming, WhenOngoing or WhenPast. Conferences whose
+	// dates aren't known yet never match.
+	When string
+	// only conferences this user can access
+	UserID int32
+}
+
+var (
+	ErrInvalidVisibility = errors.New("invalid visibility")
+	ErrNothingToArchive  = errors.New("conference has no schedule to archive yet")
+)
+
+func (s *service) GetConference(id int32) (*sqlc.Conference, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	c, ok := s.conferences[id]
+	if !ok {
+		return nil, ErrConferenceNotFound
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	details := c.details
+	return &details, nil
+}
+
+// SetVisibility changes who can see a conference. Archiving a conference
+// snapshots its schedule so that it can be served without refetching, and
+// unarchiving it fetches the schedule again.
+func (s *service) SetVisibility(id int32, visibility string) (*sqlc.Conference, error) {
+	if !slices.Contains(Visibilities, visibility) {
+		return nil, ErrInvalidVisibility
+	}
+
+	if visibility == VisibilityArchived {
+		// make sure there is a schedule to snapshot
+		if _, _, err := s.GetSchedule(id); err != nil {
+			return nil, err
+		}
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	c, ok := s.conferences[id]
+	if !ok {
+		return nil, ErrConferenceNotFound
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ctx := context.Background()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	queries := sqlc.New(s.pool).WithTx(tx)
+
+	params := sqlc.SetConferenceVisibilityParams{
+		ID:         id,
+		Visibility: visibility,
+	}
+	if visibility == VisibilityArchived {
+		if c.details.Visibility == VisibilityArchived {
+			return &c.details, nil
+		}
+
+		// the schedule may still be being fetched for the first time by
+		// another request
+		if c.source == nil {
+			return nil, ErrNothingToArchive
+		}
+
+		snapshot, err := json.Marshal(c.source)
+		if err != nil {
+			return nil, fmt.Errorf("could not snapshot schedule: %w", err)
+		}
+		if err := queries.UpsertConferenceSnapshot(ctx, sqlc.UpsertConferenceSnapshotParams{
+			ConferenceID: id,
+			Schedule:     snapshot,
+			FetchedAt:    pgtype.Timestamptz{Time: c.lastUpdated, Valid: true},
+		}); err != nil {
+			return nil, fmt.Errorf("could not save snapshot: %w", err)
+		}
+		params.ArchivedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	} else if err := queries.DeleteConferenceSnapshot(ctx, id); err != nil {
+		return nil, fmt.Errorf("could not delete snapshot: %w", err)
+	}
+
+	conference, err := queries.SetConferenceVisibility(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrConferenceNotFound
+		}
+		return nil, fmt.Errorf("could not update conference: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	if c.details.Visibility == VisibilityArchived && visibility != VisibilityArchived {
+		// the snapshot may be long out of date
+		c.lastUpdated = time.Unix(0, 0)
+	}
+	c.details = conference
+
+	return &conference, nil
+}
+
+// loadSnapshot restores an archived conference's schedule from its
+// snapshot. Conferences without one are fetched as usual.
+func (c *loadedConference) loadSnapshot(queries *sqlc.Queries) error {
+	snapshot, err := queries.GetConferenceSnapshot(context.Background(), c.details.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("could not fetch snapshot: %w", err)
+	}
+
+	var schedule Schedule
+	if err := json.Unmarshal(snapshot.Schedule, &schedule); err != nil {
+		return fmt.Errorf("could not read snapshot: %w", err)
+	}
+
+	c.source = &schedule
+	c.lastUpdated = snapshot.FetchedAt.Time
+	return c.rebuildSchedule()
+}
+
+// frozen reports whether the conference's schedule should no longer be
+// refetched. It must be called with c.lock held.
+func (c *loadedConference) frozen() bool {
+	return c.details.Visibility == VisibilityArchived && c.source != nil
+}
+
+func (f ConferenceFilter) matches(conference sqlc.Conference, today time.Time) bool {
+	if len(f.Visibilities) > 0 && !slices.Contains(f.Visibilities, conference.Visibility) {
+		return false
+	}
+	if f.When == "" {
+		return true
+	}
+	if !conference.StartsOn.Valid || !conference.EndsOn.Valid {
+		return false
+	}
+
+	switch f.When {
+	case WhenUpcoming:
+		return today.Before(conference.StartsOn.Time)
+	case WhenOngoing:
+		return !today.Before(conference.StartsOn.Time) && !today.After(conference.EndsOn.Time)
+	case WhenPast:
+		return today.After(conference.EndsOn.Time)
+	}
+	return false
+}
+
+// scheduleDates derives the dates a conference runs over from its
+// schedule.
+func scheduleDates(schedule *Schedule) (pgtype.Date, pgtype.Date) {
+	if schedule == nil || len(schedule.Days) == 0 {
+		return pgtype.Date{}, pgtype.Date{}
+	}
+
+	first, err := time.Parse(time.DateOnly, schedule.Days[0].Date)
+	if err != nil {
+		return pgtype.Date{}, pgtype.Date{}
+	}
+	last, err := time.Parse(time.DateOnly, schedule.Days[len(schedule.Days)-1].Date)
+	if err != nil {
+		return pgtype.Date{}, pgtype.Date{}
+	}
+
+	return pgtype.Date{Time: first, Valid: true}, pgtype.Date{Time: last, Valid: true}
+}
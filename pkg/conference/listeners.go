@@ -0,0 +1,112 @@
+package conference
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ScheduleUpdate is sent to schedule listeners whenever a conference's
+// schedule has been (re)fetched from its source, or its overrides or shared
+// custom events have changed, and when the conference is deleted.
+type ScheduleUpdate struct {
+	ConferenceID int32
+	// set once the conference has been deleted, in which case Schedule and
+	// Changes are nil
+	Deleted  bool
+	Schedule *Schedule
+	// how the schedule's events differ from the last update for the
+	// conference, or nil for the first update since the server started or
+	// the conference was restored, as there is nothing to compare it to
+	Changes *EventChanges
+}
+
+// ScheduleListener is called with each update to a conference's schedule.
+// Updates are delivered to each listener one at a time, in the order the
+// schedules were made.
+type ScheduleListener func(update ScheduleUpdate)
+
+var generations atomic.Uint64
+
+func (s *service) AddScheduleListener(listener ScheduleListener) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.listeners = append(s.listeners, &listenerQueue{listener: listener})
+}
+
+// notifyListeners must be called with s.lock held. Schedules older than
+// the last one the conference's listeners were told about are dropped, as
+// they may be notified out of order by concurrent refreshes.
+func (s *service) notifyListeners(conferenceID int32, schedule *Schedule) {
+	s.notifyLock.Lock()
+	defer s.notifyLock.Unlock()
+
+	update := ScheduleUpdate{
+		ConferenceID: conferenceID,
+		Schedule:     schedule,
+	}
+	if previous, ok := s.notified[conferenceID]; ok {
+		if schedule.generation <= previous.generation {
+			return
+		}
+		changes := DiffEvents(previous, schedule)
+		update.Changes = &changes
+	}
+	s.notified[conferenceID] = schedule
+
+	for _, queue := range s.listeners {
+		queue.push(update)
+	}
+}
+
+// notifyDeleted tells listeners a conference has been deleted. It must be
+// called with s.lock held.
+func (s *service) notifyDeleted(conferenceID int32) {
+	s.notifyLock.Lock()
+	defer s.notifyLock.Unlock()
+
+	delete(s.notified, conferenceID)
+
+	for _, queue := range s.listeners {
+		queue.push(ScheduleUpdate{
+			ConferenceID: conferenceID,
+			Deleted:      true,
+		})
+	}
+}
+
+// listenerQueue delivers updates to a listener in the order they were
+// pushed, without holding up whoever pushed them.
+type listenerQueue struct {
+	listener ScheduleListener
+	pending  []ScheduleUpdate
+	running  bool
+	lock     sync.Mutex
+}
+
+func (q *listenerQueue) push(update ScheduleUpdate) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.pending = append(q.pending, update)
+	if !q.running {
+		q.running = true
+		go q.drain()
+	}
+}
+
+func (q *listenerQueue) drain() {
+	for {
+		q.lock.Lock()
+		if len(q.pending) == 0 {
+			q.running = false
+			q.lock.Unlock()
+			return
+		}
+		update := q.pending[0]
+		q.pending = q.pending[1:]
+		q.lock.Unlock()
+
+		q.listener(update)
+	}
+}
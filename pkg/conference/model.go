@@ -1,11 +1,49 @@
 package conference
 
-import "time"
+import (
+	"strconv"
+	"time"
+)
 
 type Schedule struct {
 	Conference Conference `json:"conference"`
 	Tracks     []Track    `json:"tracks"`
 	Days       []Day      `json:"days"`
+	// Checksum is a hash of the schedule's contents, which changes whenever
+	// anything in the schedule does
+	Checksum string `json:"-"`
+	// generation orders the schedules served across every conference, so
+	// that listeners can be told about them in the order they were made
+	generation uint64
+}
+
+// Generation increases with every schedule made, so that a newer version of
+// a conference's schedule can be told apart from an older one.
+func (s *Schedule) Generation() uint64 {
+	return s.generation
+}
+
+// EventsByID maps the schedule's events by their IDs. IDs used by more than
+// one event are left out, as separately published sources merged into one
+// schedule often number their events the same way, and there is no telling
+// which event such an ID refers to.
+func (s *Schedule) EventsByID() map[int32]Event {
+	events := make(map[int32]Event)
+	ambiguous := make(map[int32]bool)
+	for _, day := range s.Days {
+		for _, room := range day.Rooms {
+			for _, event := range room.Events {
+				if _, ok := events[event.ID]; ok {
+					ambiguous[event.ID] = true
+				}
+				events[event.ID] = event
+			}
+		}
+	}
+	for id := range ambiguous {
+		delete(events, id)
+	}
+	return events
 }
 
 type Conference struct {
@@ -53,11 +91,31 @@ type Event struct {
 	Persons     []Person     `json:"persons"`
 	Attachments []Attachment `json:"attachments"`
 	Links       []Link       `json:"links"`
+	// set if an admin has overridden the event locally
+	Amendment *Amendment `json:"amendment,omitempty"`
+	// set if the event was authored locally rather than fetched
+	Custom *Custom `json:"custom,omitempty"`
+	// the label of the source the event was fetched from
+	Source string `json:"source,omitempty"`
+}
+
+// Key identifies the event within its schedule. It is the event's GUID, or
+// its ID for sources which don't publish GUIDs, as merged sources may reuse
+// each other's IDs.
+func (e Event) Key() string {
+	if e.GUID == "" {
+		return "id:" + strconv.Itoa(int(e.ID))
+	}
+	return e.GUID
 }
 
 type Person struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID        int    `json:"id"`
+	GUID      string `json:"guid,omitempty"`
+	Name      string `json:"name"`
+	Biography string `json:"biography,omitempty"`
+	Avatar    string `json:"avatar,omitempty"`
+	Links     []Link `json:"links,omitempty"`
 }
 
 type Attachment struct {
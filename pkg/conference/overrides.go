@@ -0,0 +1,402 @@
+package conference
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Override is a local amendment an admin makes to an event, for changes
+// announced before the source schedule catches up. Nil fields are left as
+// the source schedule has them.
+type Override struct {
+	Start     *time.Time
+	End       *time.Time
+	Room      *string
+	Title     *string
+	Cancelled bool
+	Note      *string
+}
+
+// Amendment marks an event which has been overridden locally, giving the
+// fields which differ from the source schedule and their original values.
+type Amendment struct {
+	Fields    []string  `json:"fields"`
+	Cancelled bool      `json:"cancelled"`
+	Note      string    `json:"note,omitempty"`
+	Original  Original  `json:"original"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type Original struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Room  string    `json:"room"`
+	Title string    `json:"title"`
+}
+
+var ErrInvalidOverride = errors.New("invalid override")
+
+// Cancelled reports whether an admin has marked the event as cancelled.
+func (e Event) Cancelled() bool {
+	return e.Amendment != nil && e.Amendment.Cancelled
+}
+
+func (s *service) GetOverrides(conferenceID int32) ([]sqlc.EventOverride, error) {
+	s.lock.RLock()
+	_, ok := s.conferences[conferenceID]
+	s.lock.RUnlock()
+	if !ok {
+		return nil, ErrConferenceNotFound
+	}
+
+	queries := sqlc.New(s.pool)
+
+	overrides, err := queries.GetEventOverrides(context.Background(), conferenceID)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch overrides: %w", err)
+	}
+
+	return overrides, nil
+}
+
+// SetOverride replaces any override for the event with the given GUID and
+// layers it on to the schedule straight away.
+func (s *service) SetOverride(conferenceID int32, guid string, override Override, userID int32) (*sqlc.EventOverride, error) {
+	// make sure the source schedule has been fetched to check against
+	if _, _, err := s.GetSchedule(conferenceID); err != nil {
+		return nil, err
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	c, ok := s.conferences[conferenceID]
+	if !ok {
+		return nil, ErrConferenceNotFound
+	}
+
+	var eventGUID pgtype.UUID
+	if err := eventGUID.Scan(guid); err != nil {
+		return nil, ErrEventNotFound
+	}
+
+	c.lock.RLock()
+	event, ok := findSourceEvent(c.source, eventGUID.String())
+	c.lock.RUnlock()
+	if !ok {
+		return nil, ErrEventNotFound
+	}
+
+	start, end := event.Start, event.End
+	if override.Start != nil {
+		start = *override.Start
+		end = start.Add(event.End.Sub(event.Start))
+	}
+	if override.End != nil {
+		end = *override.End
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("%w: event must end after it starts", ErrInvalidOverride)
+	}
+
+	params := sqlc.UpsertEventOverrideParams{
+		ConferenceID: conferenceID,
+		EventGuid:    eventGUID,
+		Cancelled:    override.Cancelled,
+		UpdatedBy:    pgtype.Int4{Int32: userID, Valid: true},
+	}
+	if override.Start != nil {
+		params.StartTime = pgtype.Timestamptz{Time: *override.Start, Valid: true}
+	}
+	if override.End != nil {
+		params.EndTime = pgtype.Timestamptz{Time: *override.End, Valid: true}
+	}
+	if override.Room != nil {
+		params.Room = pgtype.Text{String: *override.Room, Valid: true}
+	}
+	if override.Title != nil {
+		params.Title = pgtype.Text{String: *override.Title, Valid: true}
+	}
+	if override.Note != nil {
+		params.Note = pgtype.Text{String: *override.Note, Valid: true}
+	}
+
+	queries := sqlc.New(s.pool)
+
+	stored, err := queries.UpsertEventOverride(context.Background(), params)
+	if err != nil {
+		return nil, fmt.Errorf("could not save override: %w", err)
+	}
+
+	c.lock.Lock()
+	c.overrides[stored.EventGuid.String()] = stored
+	err = c.rebuildSchedule()
+	schedule := c.schedule
+	c.lock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyListeners(conferenceID, schedule)
+
+	return &stored, nil
+}
+
+// DeleteOverride removes the override for an event, restoring it to how
+// the source schedule has it.
+func (s *service) DeleteOverride(conferenceID int32, guid string) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	c, ok := s.conferences[conferenceID]
+	if !ok {
+		return ErrConferenceNotFound
+	}
+
+	var eventGUID pgtype.UUID
+	if err := eventGUID.Scan(guid); err != nil {
+		return ErrOverrideNotFound
+	}
+
+	queries := sqlc.New(s.pool)
+
+	rowsAffected, err := queries.DeleteEventOverride(context.Background(), sqlc.DeleteEventOverrideParams{
+		ConferenceID: conferenceID,
+		EventGuid:    eventGUID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete override: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrOverrideNotFound
+	}
+
+	c.lock.Lock()
+	delete(c.overrides, eventGUID.String())
+	err = c.rebuildSchedule()
+	schedule := c.schedule
+	c.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if schedule != nil {
+		s.notifyListeners(conferenceID, schedule)
+	}
+
+	return nil
+}
+
+func loadOverrides(queries *sqlc.Queries, conferenceID int32) (map[string]sqlc.EventOverride, error) {
+	stored, err := queries.GetEventOverrides(context.Background(), conferenceID)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch overrides: %w", err)
+	}
+
+	overrides := make(map[string]sqlc.EventOverride, len(stored))
+	for _, override := range stored {
+		overrides[override.EventGuid.String()] = override
+	}
+	return overrides, nil
+}
+
+func findSourceEvent(schedule *Schedule, guid string) (Event, bool) {
+	if schedule == nil {
+		return Event{}, false
+	}
+	for _, day := range schedule.Days {
+		for _, room := range day.Rooms {
+			for _, event := range room.Events {
+				if strings.EqualFold(event.GUID, guid) {
+					return event, true
+				}
+			}
+		}
+	}
+	return Event{}, false
+}
+
+// rebuildSchedule rebuilds the schedule served to clients by adding any
+// shared custom events to the source schedule and layering the overrides
+// on top, and gives it any title override. Events which have moved are
+// placed in the day and room they have moved to. It must be called with
+// c.lock held.
+func (c *loadedConference) rebuildSchedule() error {
+	if c.source == nil {
+		return nil
+	}
+	if len(c.overrides) == 0 && len(c.customEvents) == 0 {
+		return c.setSchedule(c.retitle(c.source))
+	}
+
+	schedule := cloneSchedule(c.source, false)
+
+	for i, day := range c.source.Days {
+		for _, room := range day.Rooms {
+			for _, event := range room.Events {
+				override, ok := c.overrides[strings.ToLower(event.GUID)]
+				if !ok {
+					schedule.Days[i].addEvent(event)
+					continue
+				}
+				schedule.placeEvent(amend(event, override), &schedule.Days[i])
+			}
+		}
+	}
+
+	location := schedule.location()
+	for _, custom := range c.customEvents {
+		schedule.placeEvent(customEvent(custom, location), nil)
+	}
+
+	schedule.sortEvents()
+
+	return c.setSchedule(c.retitle(schedule))
+}
+
+// cloneSchedule copies a schedule so that events can be added to it without
+// changing the original. The copy's rooms are left empty unless withEvents
+// is set.
+func cloneSchedule(src *Schedule, withEvents bool) *Schedule {
+	schedule := &Schedule{
+		Conference: src.Conference,
+		Tracks:     src.Tracks,
+		Days:       make([]Day, len(src.Days)),
+	}
+	for i, day := range src.Days {
+		schedule.Days[i] = Day{
+			Date:  day.Date,
+			Start: day.Start,
+			End:   day.End,
+			Rooms: make([]Room, len(day.Rooms)),
+		}
+		for j, room := range day.Rooms {
+			schedule.Days[i].Rooms[j] = Room{
+				Name:   room.Name,
+				Events: make([]Event, 0, len(room.Events)),
+			}
+			if withEvents {
+				schedule.Days[i].Rooms[j].Events = append(schedule.Days[i].Rooms[j].Events, room.Events...)
+			}
+		}
+	}
+	return schedule
+}
+
+// placeEvent adds an event to the day it starts in. Events starting outside
+// every day go in fallback, or a new day if fallback is nil.
+func (s *Schedule) placeEvent(event Event, fallback *Day) {
+	if fallback != nil && !event.Start.Before(fallback.Start) && event.Start.Before(fallback.End) {
+		fallback.addEvent(event)
+		return
+	}
+	for i := range s.Days {
+		if !event.Start.Before(s.Days[i].Start) && event.Start.Before(s.Days[i].End) {
+			s.Days[i].addEvent(event)
+			return
+		}
+	}
+	if fallback != nil {
+		fallback.addEvent(event)
+		return
+	}
+
+	start := time.Date(event.Start.Year(), event.Start.Month(), event.Start.Day(), 0, 0, 0, 0, event.Start.Location())
+	day := Day{
+		Date:  start.Format(time.DateOnly),
+		Start: start,
+		End:   start.AddDate(0, 0, 1),
+	}
+	day.addEvent(event)
+
+	i := sort.Search(len(s.Days), func(i int) bool {
+		return s.Days[i].Start.After(day.Start)
+	})
+	s.Days = slices.Insert(s.Days, i, day)
+}
+
+// sortEvents orders the events in each room by their start time.
+func (s *Schedule) sortEvents() {
+	for _, day := range s.Days {
+		for _, room := range day.Rooms {
+			sort.SliceStable(room.Events, func(i, j int) bool {
+				return room.Events[i].Start.Before(room.Events[j].Start)
+			})
+		}
+	}
+}
+
+// addEvent adds an event to the day under its room, adding the room if the
+// day doesn't have it yet.
+func (d *Day) addEvent(event Event) {
+	for i := range d.Rooms {
+		if d.Rooms[i].Name == event.Room {
+			d.Rooms[i].Events = append(d.Rooms[i].Events, event)
+			return
+		}
+	}
+	d.Rooms = append(d.Rooms, Room{
+		Name:   event.Room,
+		Events: []Event{event},
+	})
+}
+
+// amend applies an override to an event, marking the event with what was
+// changed.
+func amend(event Event, override sqlc.EventOverride) Event {
+	original := event
+	location := event.Start.Location()
+
+	if override.StartTime.Valid {
+		length := event.End.Sub(event.Start)
+		event.Start = override.StartTime.Time.In(location)
+		event.End = event.Start.Add(length)
+		event.Date = event.Start.Format(time.RFC3339)
+	}
+	if override.EndTime.Valid && override.EndTime.Time.After(event.Start) {
+		event.End = override.EndTime.Time.In(location)
+	}
+	event.Duration = int32(event.End.Sub(event.Start).Minutes())
+	if override.Room.Valid {
+		event.Room = override.Room.String
+	}
+	if override.Title.Valid {
+		event.Title = override.Title.String
+	}
+
+	amendment := &Amendment{
+		Fields:    make([]string, 0),
+		Cancelled: override.Cancelled,
+		Note:      override.Note.String,
+		Original: Original{
+			Start: original.Start,
+			End:   original.End,
+			Room:  original.Room,
+			Title: original.Title,
+		},
+		UpdatedAt: override.UpdatedAt.Time,
+	}
+	if !event.Start.Equal(original.Start) {
+		amendment.Fields = append(amendment.Fields, "start")
+	}
+	if !event.End.Equal(original.End) {
+		amendment.Fields = append(amendment.Fields, "end")
+	}
+	if event.Room != original.Room {
+		amendment.Fields = append(amendment.Fields, "room")
+	}
+	if event.Title != original.Title {
+		amendment.Fields = append(amendment.Fields, "title")
+	}
+	event.Amendment = amendment
+
+	return event
+}
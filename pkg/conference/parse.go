@@ -3,6 +3,7 @@ package conference
 import (
 	"encoding/xml"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -61,7 +62,14 @@ type event struct {
 
 type person struct {
 	ID   int    `xml:"id,attr"`
+	GUID string `xml:"guid,attr"`
 	Name string `xml:",chardata"`
+	// some generators nest the speaker's details rather than giving their
+	// name as the element's text
+	FullName  string `xml:"name"`
+	Biography string `xml:"biography"`
+	Avatar    string `xml:"avatar"`
+	Links     []link `xml:"links>link"`
 }
 
 type attachment struct {
@@ -182,7 +190,18 @@ func (dst *Event) Scan(src event) error {
 
 func (dst *Person) Scan(src person) {
 	dst.ID = src.ID
-	dst.Name = src.Name
+	dst.GUID = src.GUID
+	dst.Name = strings.TrimSpace(src.Name)
+	if dst.Name == "" {
+		dst.Name = strings.TrimSpace(src.FullName)
+	}
+	dst.Biography = strings.TrimSpace(src.Biography)
+	dst.Avatar = strings.TrimSpace(src.Avatar)
+
+	dst.Links = make([]Link, len(src.Links))
+	for i := range src.Links {
+		dst.Links[i].Scan(src.Links[i])
+	}
 }
 
 func (dst *Attachment) Scan(src attachment) {
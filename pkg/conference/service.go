@@ -3,9 +3,13 @@ package conference
 import (
 	"bufio"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"sync"
 	"time"
@@ -17,37 +21,87 @@ import (
 
 type Service interface {
 	CreateConference(url string) (*sqlc.Conference, error)
-	DeleteConference(id int32) error
-	GetConferences() ([]sqlc.Conference, error)
+	CreateCustomConference(details CustomConference) (*sqlc.Conference, error)
+	DeleteConference(id int32) (*sqlc.Conference, error)
+	GetDeletedConferences() ([]sqlc.Conference, error)
+	RestoreConference(id int32) (*sqlc.Conference, error)
+	GetDeletionImpact(id int32) (*DeletionImpact, error)
+	Retention() time.Duration
+	GetConferences(filter ConferenceFilter) ([]sqlc.Conference, error)
+	GetConference(id int32) (*sqlc.Conference, error)
+	SetVisibility(id int32, visibility string) (*sqlc.Conference, error)
+	GetAccess(conferenceID int32) (*Access, error)
+	SetAccess(conferenceID int32, access Access) (*Access, error)
+	CanAccess(conferenceID int32, userID int32) (bool, error)
+	UpdateConference(id int32, update ConferenceUpdate) (*sqlc.Conference, error)
 	GetSchedule(id int32) (*Schedule, time.Time, error)
+	GetScheduleForUser(id int32, userID int32) (*Schedule, time.Time, error)
 	GetEventByID(conferenceID, eventID int32) (*Event, error)
+	GetEventByGUID(conferenceID int32, guid string, userID int32) (*Event, error)
+	GetEvents(conferenceID int32, filter EventFilter, cursor string, limit int) (*EventPage, error)
+	GetSpeakers(conferenceID int32) ([]Speaker, error)
+	GetSpeaker(conferenceID int32, personID int) (*Speaker, error)
+	GetOverrides(conferenceID int32) ([]sqlc.EventOverride, error)
+	SetOverride(conferenceID int32, guid string, override Override, userID int32) (*sqlc.EventOverride, error)
+	DeleteOverride(conferenceID int32, guid string) error
+	GetCustomEvents(conferenceID int32, userID int32) ([]sqlc.CustomEvent, error)
+	GetCustomEventsOwnedByUser(userID int32) ([]sqlc.CustomEvent, error)
+	GetCustomEvent(conferenceID int32, id int32) (*sqlc.CustomEvent, error)
+	CreateCustomEvent(conferenceID int32, userID int32, details CustomEventDetails) (*sqlc.CustomEvent, error)
+	UpdateCustomEvent(conferenceID int32, id int32, details CustomEventDetails) (*sqlc.CustomEvent, error)
+	DeleteCustomEvent(conferenceID int32, id int32) error
+	GetSources(conferenceID int32) ([]Source, []MergeConflict, error)
+	AddSource(conferenceID int32, url string, label string) (*sqlc.ConferenceSource, error)
+	DeleteSource(conferenceID int32, sourceID int32) error
+	AddScheduleListener(listener ScheduleListener)
 }
 
 type loadedConference struct {
-	pentabarfUrl string
+	details sqlc.Conference
+	// source is the schedule as fetched, and schedule is the source with
+	// shared custom events added and overrides applied
+	source       *Schedule
 	schedule     *Schedule
+	overrides    map[string]sqlc.EventOverride
+	customEvents []sqlc.CustomEvent
+	// extra feeds merged with the main url, the last schedule fetched from
+	// each source by label, and the events they disagree on
+	sources      []sqlc.ConferenceSource
+	feeds        map[string]*feed
+	conflicts    []MergeConflict
 	eventsById   map[int32]Event
+	eventsByGuid map[string]Event
 	lastUpdated  time.Time
 	lock         sync.RWMutex
 }
 
 var (
 	ErrConferenceNotFound = errors.New("conference not found")
+	ErrEventNotFound      = errors.New("event not found")
 	ErrScheduleFetch      = errors.New("could not fetch schedule")
+	ErrOverrideNotFound   = errors.New("override not found")
 )
 
 type service struct {
 	conferences map[int32]*loadedConference
-	lock        sync.RWMutex
-	pool        *pgxpool.Pool
+	listeners   []*listenerQueue
+	// the last schedule each conference's listeners were told about
+	notified   map[int32]*Schedule
+	notifyLock sync.Mutex
+	lock       sync.RWMutex
+	pool       *pgxpool.Pool
+	// how long deleted conferences are kept before being purged
+	retention time.Duration
 }
 
 // TODO: Create a service implementation that persists to DB
 // and isn't in memory
-func NewService(pool *pgxpool.Pool) (Service, error) {
+func NewService(pool *pgxpool.Pool, retention time.Duration) (Service, error) {
 	service := &service{
 		pool:        pool,
 		conferences: make(map[int32]*loadedConference),
+		notified:    make(map[int32]*Schedule),
+		retention:   retention,
 	}
 
 	queries := sqlc.New(pool)
@@ -57,25 +111,58 @@ func NewService(pool *pgxpool.Pool) (Service, error) {
 	}
 
 	for _, conference := range conferences {
-		c := &loadedConference{
-			pentabarfUrl: conference.Url,
-			lastUpdated:  time.Unix(0, 0),
+		c, err := loadConference(queries, conference)
+		if err != nil {
+			return nil, err
 		}
 		service.conferences[conference.ID] = c
 	}
 
+	go service.runPurge()
+
 	return service, nil
 }
 
+// loadConference loads everything stored locally for a conference, ready
+// for its schedule to be fetched.
+func loadConference(queries *sqlc.Queries, conference sqlc.Conference) (*loadedConference, error) {
+	overrides, err := loadOverrides(queries, conference.ID)
+	if err != nil {
+		return nil, err
+	}
+	customEvents, err := queries.GetSharedCustomEvents(context.Background(), conference.ID)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := queries.GetConferenceSources(context.Background(), conference.ID)
+	if err != nil {
+		return nil, err
+	}
+	c := &loadedConference{
+		details:      conference,
+		overrides:    overrides,
+		customEvents: customEvents,
+		sources:      sources,
+		lastUpdated:  time.Unix(0, 0),
+	}
+	if conference.Visibility == VisibilityArchived {
+		if err := c.loadSnapshot(queries); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
 func (s *service) CreateConference(url string) (*sqlc.Conference, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
 	c := &loadedConference{
-		pentabarfUrl: url,
-		lastUpdated:  time.Unix(0, 0),
+		details:     sqlc.Conference{Url: pgtype.Text{String: url, Valid: true}},
+		overrides:   make(map[string]sqlc.EventOverride),
+		lastUpdated: time.Unix(0, 0),
 	}
-	err := c.updateSchedule()
+	_, err := c.updateSchedule()
 	if err != nil {
 		return nil, errors.Join(ErrScheduleFetch, err)
 	}
@@ -83,7 +170,7 @@ func (s *service) CreateConference(url string) (*sqlc.Conference, error) {
 	queries := sqlc.New(s.pool)
 
 	conference, err := queries.CreateConference(context.Background(), sqlc.CreateConferenceParams{
-		Url:   url,
+		Url:   pgtype.Text{String: url, Valid: true},
 		Title: pgtype.Text{String: c.schedule.Conference.Title, Valid: true},
 		Venue: pgtype.Text{String: c.schedule.Conference.Venue, Valid: true},
 		City:  pgtype.Text{String: c.schedule.Conference.City, Valid: true},
@@ -92,31 +179,43 @@ func (s *service) CreateConference(url string) (*sqlc.Conference, error) {
 		return nil, fmt.Errorf("could not create conference: %w", err)
 	}
 
+	c.details = conference
 	s.conferences[conference.ID] = c
+	s.notifyListeners(conference.ID, c.schedule)
 
 	return &conference, nil
 }
 
-func (s *service) DeleteConference(id int32) error {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+func (s *service) GetConferences(filter ConferenceFilter) ([]sqlc.Conference, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
 	queries := sqlc.New(s.pool)
-	err := queries.DeleteConference(context.Background(), id)
+	conferences, err := queries.GetConferences(context.Background())
 	if err != nil {
-		return fmt.Errorf("could not delete conference: %w", err)
+		return nil, err
 	}
 
-	delete(s.conferences, id)
-	return nil
-}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
 
-func (s *service) GetConferences() ([]sqlc.Conference, error) {
-	s.lock.RLock()
-	defer s.lock.RUnlock()
-
-	queries := sqlc.New(s.pool)
-	return queries.GetConferences(context.Background())
+	filtered := make([]sqlc.Conference, 0, len(conferences))
+	for _, conference := range conferences {
+		if !filter.matches(conference, today) {
+			continue
+		}
+		if filter.UserID != 0 {
+			ok, err := s.canAccess(conference, filter.UserID)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		filtered = append(filtered, conference)
+	}
+	return filtered, nil
 }
 
 func (s *service) GetSchedule(id int32) (*Schedule, time.Time, error) {
@@ -128,24 +227,45 @@ func (s *service) GetSchedule(id int32) (*Schedule, time.Time, error) {
 		return nil, time.Time{}, ErrConferenceNotFound
 	}
 
-	if err := c.updateSchedule(); err != nil {
+	updated, err := c.updateSchedule()
+	if err != nil {
 		return nil, time.Time{}, err
 	}
 
 	c.lock.RLock()
-	defer c.lock.RUnlock()
+	source, schedule, lastUpdated, details := c.source, c.schedule, c.lastUpdated, c.details
+	c.lock.RUnlock()
+
+	// the title is cached as the source has it, so that a title override
+	// can be removed again
+	params := sqlc.UpdateConferenceDetailsParams{
+		ID:       id,
+		Title:    pgtype.Text{String: source.Conference.Title, Valid: true},
+		Venue:    pgtype.Text{String: source.Conference.Venue, Valid: true},
+		City:     pgtype.Text{String: source.Conference.City, Valid: true},
+		StartsOn: details.StartsOn,
+		EndsOn:   details.EndsOn,
+	}
+	if details.Url.Valid {
+		// custom conferences have their dates set explicitly
+		params.StartsOn, params.EndsOn = scheduleDates(schedule)
+	}
 
 	queries := sqlc.New(s.pool)
-	if _, err := queries.UpdateConferenceDetails(context.Background(), sqlc.UpdateConferenceDetailsParams{
-		ID:    id,
-		Title: pgtype.Text{String: c.schedule.Conference.Title, Valid: true},
-		Venue: pgtype.Text{String: c.schedule.Conference.Venue, Valid: true},
-		City:  pgtype.Text{String: c.schedule.Conference.City, Valid: true},
-	}); err != nil {
+	conference, err := queries.UpdateConferenceDetails(context.Background(), params)
+	if err != nil {
 		return nil, time.Time{}, fmt.Errorf("failed to update cached conference details: %w", err)
 	}
 
-	return c.schedule, c.lastUpdated, nil
+	c.lock.Lock()
+	c.details = conference
+	c.lock.Unlock()
+
+	if updated {
+		s.notifyListeners(id, schedule)
+	}
+
+	return schedule, lastUpdated, nil
 }
 
 func (s *service) GetEventByID(conferenceID, eventID int32) (*Event, error) {
@@ -160,31 +280,98 @@ func (s *service) GetEventByID(conferenceID, eventID int32) (*Event, error) {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
 
-	event := c.eventsById[eventID]
+	event, ok := c.eventsById[eventID]
+	if !ok {
+		return nil, ErrEventNotFound
+	}
+
+	return &event, nil
+}
+
+// GetEventByGUID finds an event in a conference's schedule, or one of the
+// user's own personal custom events. A userID of 0 only finds events in the
+// schedule.
+func (s *service) GetEventByGUID(conferenceID int32, guid string, userID int32) (*Event, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	c, ok := s.conferences[conferenceID]
+	if !ok {
+		return nil, ErrConferenceNotFound
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	event, ok := c.eventsByGuid[guid]
+	if !ok {
+		if c.schedule == nil {
+			return nil, ErrEventNotFound
+		}
+		return s.lookupPersonalEvent(conferenceID, guid, userID, c.schedule.location())
+	}
 
 	return &event, nil
 }
 
 func (c *loadedConference) hasScheduleExpired() bool {
-	expire := c.lastUpdated.Add(15 * time.Minute)
+	interval := time.Duration(c.details.RefreshMinutes) * time.Minute
+	expire := c.lastUpdated.Add(interval)
 	return time.Now().After(expire)
 }
 
-func (c *loadedConference) updateSchedule() error {
+func (c *loadedConference) updateSchedule() (bool, error) {
 	if !c.hasScheduleExpired() {
-		return nil
+		return false, nil
 	}
 
 	if !c.lock.TryLock() {
 		// don't block if another goroutine is already fetching
-		return nil
+		return false, nil
 	}
 	defer c.lock.Unlock()
 
-	res, err := http.Get(c.pentabarfUrl)
+	if c.frozen() {
+		return false, nil
+	}
+
+	var newSchedule *Schedule
+	var err error
+	if c.details.Url.Valid {
+		newSchedule, err = fetchSchedule(c.details.Url.String)
+	} else {
+		newSchedule, err = customSchedule(c.details)
+	}
+	if c.feeds == nil {
+		c.feeds = make(map[string]*feed)
+	}
+	if err != nil {
+		if c.details.Url.Valid {
+			c.feeds[MainSource] = &feed{schedule: c.feeds[MainSource].lastSchedule(), fetchedAt: time.Now(), err: err}
+		}
+		return false, err
+	}
+	tagSource(newSchedule, MainSource)
+	c.feeds[MainSource] = &feed{schedule: newSchedule, fetchedAt: time.Now()}
+
+	c.source, c.conflicts = mergeSchedules(newSchedule, c.fetchSources())
+	if len(c.conflicts) > 0 {
+		slog.Warn("conflicting events in conference sources", "url", c.details.Url.String, "conflicts", len(c.conflicts))
+	}
+	if err := c.rebuildSchedule(); err != nil {
+		return false, err
+	}
+	c.lastUpdated = time.Now()
+
+	return true, nil
+}
+
+func fetchSchedule(url string) (*Schedule, error) {
+	res, err := http.Get(url)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer res.Body.Close()
 
 	reader := bufio.NewReader(res.Body)
 
@@ -192,24 +379,38 @@ func (c *loadedConference) updateSchedule() error {
 
 	decoder := xml.NewDecoder(reader)
 	if err := decoder.Decode(&schedule); err != nil {
-		return fmt.Errorf("failed to decode XML: %w", err)
+		return nil, fmt.Errorf("failed to decode XML: %w", err)
 	}
 
 	var newSchedule Schedule
 	err = newSchedule.Scan(schedule)
 	if err != nil {
-		return fmt.Errorf("failed to scan schedule: %w", err)
+		return nil, fmt.Errorf("failed to scan schedule: %w", err)
 	}
 
-	c.schedule = &newSchedule
-	c.lastUpdated = time.Now()
+	return &newSchedule, nil
+}
+
+// setSchedule makes schedule the one served to clients. It must be called
+// with c.lock held.
+func (c *loadedConference) setSchedule(schedule *Schedule) error {
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return fmt.Errorf("failed to hash schedule: %w", err)
+	}
+	checksum := sha256.Sum256(data)
+	schedule.Checksum = hex.EncodeToString(checksum[:])
+	schedule.generation = generations.Add(1)
+
+	c.schedule = schedule
 
-	c.eventsById = make(map[int32]Event)
+	c.eventsById = schedule.EventsByID()
+	c.eventsByGuid = make(map[string]Event)
 
-	for _, day := range newSchedule.Days {
+	for _, day := range schedule.Days {
 		for _, room := range day.Rooms {
 			for _, event := range room.Events {
-				c.eventsById[event.ID] = event
+				c.eventsByGuid[event.GUID] = event
 			}
 		}
 	}
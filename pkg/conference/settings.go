@@ -0,0 +1,134 @@
+package conference
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ConferenceUpdate changes a conference's settings in place. Nil fields are
+// left as they are, and an empty Title removes the title override.
+type ConferenceUpdate struct {
+	URL             *string
+	RefreshInterval *time.Duration
+	Title           *string
+	Visibility      *string
+}
+
+var ErrInvalidRefreshInterval = errors.New("refresh interval must be between a minute and a day")
+
+// UpdateConference applies changes to a conference's settings. A new URL
+// is fetched before switching to it, and the favourites made against the
+// old schedule are kept and reconciled with the new one. Archived
+// conferences switch to a new URL once they are unarchived.
+func (s *service) UpdateConference(id int32, update ConferenceUpdate) (*sqlc.Conference, error) {
+	if update.RefreshInterval != nil && (*update.RefreshInterval < time.Minute || *update.RefreshInterval > 24*time.Hour) {
+		return nil, ErrInvalidRefreshInterval
+	}
+	if update.Visibility != nil && !slices.Contains(Visibilities, *update.Visibility) {
+		return nil, ErrInvalidVisibility
+	}
+
+	current, err := s.GetConference(id)
+	if err != nil {
+		return nil, err
+	}
+
+	params := sqlc.UpdateConferenceSettingsParams{
+		ID:             id,
+		Url:            current.Url,
+		RefreshMinutes: current.RefreshMinutes,
+		TitleOverride:  current.TitleOverride,
+	}
+
+	urlChanged := update.URL != nil && (!current.Url.Valid || *update.URL != current.Url.String)
+	if urlChanged {
+		if _, err := fetchSchedule(*update.URL); err != nil {
+			return nil, errors.Join(ErrScheduleFetch, err)
+		}
+		params.Url = pgtype.Text{String: *update.URL, Valid: true}
+	}
+	if update.RefreshInterval != nil {
+		params.RefreshMinutes = int32(update.RefreshInterval.Minutes())
+	}
+	if update.Title != nil {
+		params.TitleOverride = pgtype.Text{String: *update.Title, Valid: *update.Title != ""}
+	}
+
+	if err := s.applySettings(params, urlChanged); err != nil {
+		return nil, err
+	}
+
+	if update.Visibility != nil && *update.Visibility != current.Visibility {
+		if _, err := s.SetVisibility(id, *update.Visibility); err != nil {
+			return nil, err
+		}
+	}
+
+	// refetch if the URL changed, and store the conference's details as
+	// they now are
+	if _, _, err := s.GetSchedule(id); err != nil {
+		return nil, err
+	}
+
+	return s.GetConference(id)
+}
+
+func (s *service) applySettings(params sqlc.UpdateConferenceSettingsParams, urlChanged bool) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	c, ok := s.conferences[params.ID]
+	if !ok {
+		return ErrConferenceNotFound
+	}
+
+	queries := sqlc.New(s.pool)
+
+	conference, err := queries.UpdateConferenceSettings(context.Background(), params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrConferenceNotFound
+		}
+		return fmt.Errorf("could not update conference: %w", err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	titleChanged := c.details.TitleOverride != conference.TitleOverride
+	c.details = conference
+	if urlChanged {
+		delete(c.feeds, MainSource)
+		c.lastUpdated = time.Unix(0, 0)
+	}
+
+	if titleChanged {
+		if err := c.rebuildSchedule(); err != nil {
+			return err
+		}
+		if c.schedule != nil {
+			s.notifyListeners(params.ID, c.schedule)
+		}
+	}
+
+	return nil
+}
+
+// retitle gives a schedule the conference's title override, if it has one.
+// It must be called with c.lock held.
+func (c *loadedConference) retitle(schedule *Schedule) *Schedule {
+	if !c.details.TitleOverride.Valid {
+		return schedule
+	}
+
+	retitled := *schedule
+	retitled.Conference.Title = c.details.TitleOverride.String
+	return &retitled
+}
@@ -0,0 +1,368 @@
+package conference
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// MainSource is the label given to events from a conference's main url.
+const MainSource = "main"
+
+// Source is a schedule feed merged into a conference. The main source has
+// an ID of zero.
+type Source struct {
+	ID    int32
+	Label string
+	URL   string
+	// the number of events the source had when last fetched
+	Events    int
+	FetchedAt time.Time
+	// set if the last fetch failed, in which case the events from the
+	// fetch before are used
+	Error string
+}
+
+// MergeConflict is an event which more than one source publishes. Sources
+// lists them in the order they were merged; the event from the first is
+// kept. A conflict in "id" means two different events share an ID, in
+// which case both are kept but neither can be found by its ID, only by its
+// GUID.
+type MergeConflict struct {
+	GUID    string
+	Title   string
+	Sources []string
+	Fields  []string
+}
+
+// feed is the last schedule fetched from a source.
+type feed struct {
+	schedule  *Schedule
+	fetchedAt time.Time
+	err       error
+}
+
+func (f *feed) lastSchedule() *Schedule {
+	if f == nil {
+		return nil
+	}
+	return f.schedule
+}
+
+var (
+	ErrSourceNotFound  = errors.New("source not found")
+	ErrDuplicateSource = errors.New("conference already has a source with this label or url")
+)
+
+func (s *service) GetSources(conferenceID int32) ([]Source, []MergeConflict, error) {
+	// make sure the sources have been fetched at least once
+	if _, _, err := s.GetSchedule(conferenceID); err != nil {
+		return nil, nil, err
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	c, ok := s.conferences[conferenceID]
+	if !ok {
+		return nil, nil, ErrConferenceNotFound
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	sources := make([]Source, 0, len(c.sources)+1)
+	if c.details.Url.Valid {
+		sources = append(sources, c.describeSource(0, MainSource, c.details.Url.String))
+	}
+	for _, source := range c.sources {
+		sources = append(sources, c.describeSource(source.ID, source.Label, source.Url))
+	}
+
+	return sources, c.conflicts, nil
+}
+
+// AddSource adds a feed to be merged into the conference, checking that
+// it can be fetched first.
+func (s *service) AddSource(conferenceID int32, url string, label string) (*sqlc.ConferenceSource, error) {
+	if label == MainSource {
+		return nil, ErrDuplicateSource
+	}
+	if !s.hasConference(conferenceID) {
+		return nil, ErrConferenceNotFound
+	}
+
+	if _, err := fetchSchedule(url); err != nil {
+		return nil, errors.Join(ErrScheduleFetch, err)
+	}
+
+	queries := sqlc.New(s.pool)
+
+	source, err := queries.CreateConferenceSource(context.Background(), sqlc.CreateConferenceSourceParams{
+		ConferenceID: conferenceID,
+		Url:          url,
+		Label:        label,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrDuplicateSource
+		}
+		return nil, fmt.Errorf("could not add source: %w", err)
+	}
+
+	if err := s.reloadSources(conferenceID); err != nil {
+		return nil, err
+	}
+
+	return &source, nil
+}
+
+func (s *service) DeleteSource(conferenceID int32, sourceID int32) error {
+	queries := sqlc.New(s.pool)
+
+	rowsAffected, err := queries.DeleteConferenceSource(context.Background(), sqlc.DeleteConferenceSourceParams{
+		ID:           sourceID,
+		ConferenceID: conferenceID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete source: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrSourceNotFound
+	}
+
+	return s.reloadSources(conferenceID)
+}
+
+// reloadSources fetches the conference's list of sources and refetches
+// its schedule from them straight away.
+func (s *service) reloadSources(conferenceID int32) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	c, ok := s.conferences[conferenceID]
+	if !ok {
+		return ErrConferenceNotFound
+	}
+
+	queries := sqlc.New(s.pool)
+
+	sources, err := queries.GetConferenceSources(context.Background(), conferenceID)
+	if err != nil {
+		return fmt.Errorf("could not fetch sources: %w", err)
+	}
+
+	c.lock.Lock()
+	c.sources = sources
+	c.lastUpdated = time.Unix(0, 0)
+	c.lock.Unlock()
+
+	updated, err := c.updateSchedule()
+	if err != nil {
+		return err
+	}
+
+	if updated {
+		c.lock.RLock()
+		s.notifyListeners(conferenceID, c.schedule)
+		c.lock.RUnlock()
+	}
+
+	return nil
+}
+
+// describeSource must be called with c.lock held.
+func (c *loadedConference) describeSource(id int32, label string, url string) Source {
+	source := Source{
+		ID:    id,
+		Label: label,
+		URL:   url,
+	}
+	if feed, ok := c.feeds[label]; ok {
+		if feed.schedule != nil {
+			source.Events = countEvents(feed.schedule)
+		}
+		source.FetchedAt = feed.fetchedAt
+		if feed.err != nil {
+			source.Error = feed.err.Error()
+		}
+	}
+	return source
+}
+
+// fetchSources fetches every source other than the main one, returning
+// their schedules in order. A source which can't be fetched keeps the
+// schedule it had before, if any. It must be called with c.lock held.
+func (c *loadedConference) fetchSources() []*Schedule {
+	if c.feeds == nil {
+		c.feeds = make(map[string]*feed)
+	}
+
+	fetched := make([]feed, len(c.sources))
+
+	var wg sync.WaitGroup
+	for i, source := range c.sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			schedule, err := fetchSchedule(source.Url)
+			fetched[i] = feed{schedule: schedule, fetchedAt: time.Now(), err: err}
+		}()
+	}
+	wg.Wait()
+
+	feeds := make(map[string]*feed, len(c.sources)+1)
+	if main, ok := c.feeds[MainSource]; ok {
+		feeds[MainSource] = main
+	}
+
+	schedules := make([]*Schedule, 0, len(c.sources))
+	for i, source := range c.sources {
+		result := fetched[i]
+		if result.err != nil {
+			slog.Warn("could not fetch conference source", "source", source.Label, "url", source.Url, "error", result.err)
+			if previous, ok := c.feeds[source.Label]; ok {
+				result.schedule = previous.schedule
+			}
+		} else {
+			tagSource(result.schedule, source.Label)
+		}
+
+		feeds[source.Label] = &result
+		if result.schedule != nil {
+			schedules = append(schedules, result.schedule)
+		}
+	}
+	c.feeds = feeds
+
+	return schedules
+}
+
+// mergeSchedules merges the events of other schedules into the main one,
+// taking the conference details from main. Events are matched by GUID, so
+// an event published in several schedules is only included once.
+func mergeSchedules(main *Schedule, others []*Schedule) (*Schedule, []MergeConflict) {
+	conflicts := make([]MergeConflict, 0)
+	if len(others) == 0 {
+		return main, conflicts
+	}
+
+	merged := cloneSchedule(main, true)
+
+	tracks := make(map[string]bool)
+	for _, track := range main.Tracks {
+		tracks[track.Name] = true
+	}
+	merged.Tracks = append(make([]Track, 0, len(main.Tracks)), main.Tracks...)
+
+	byGUID := make(map[string]Event)
+	byID := make(map[int32]Event)
+	forEachEvent(main, func(event Event) {
+		if event.GUID != "" {
+			byGUID[strings.ToLower(event.GUID)] = event
+		}
+		byID[event.ID] = event
+	})
+
+	for _, other := range others {
+		for _, track := range other.Tracks {
+			if !tracks[track.Name] {
+				tracks[track.Name] = true
+				merged.Tracks = append(merged.Tracks, track)
+			}
+		}
+
+		forEachEvent(other, func(event Event) {
+			if event.GUID != "" {
+				if existing, ok := byGUID[strings.ToLower(event.GUID)]; ok {
+					if fields := differingFields(existing, event); len(fields) > 0 {
+						conflicts = append(conflicts, MergeConflict{
+							GUID:    existing.GUID,
+							Title:   existing.Title,
+							Sources: []string{existing.Source, event.Source},
+							Fields:  fields,
+						})
+					}
+					return
+				}
+				byGUID[strings.ToLower(event.GUID)] = event
+			}
+
+			if existing, ok := byID[event.ID]; ok {
+				conflicts = append(conflicts, MergeConflict{
+					GUID:    event.GUID,
+					Title:   event.Title,
+					Sources: []string{existing.Source, event.Source},
+					Fields:  []string{"id"},
+				})
+			} else {
+				byID[event.ID] = event
+			}
+
+			merged.placeEvent(event, nil)
+		})
+	}
+
+	merged.sortEvents()
+
+	if len(merged.Days) > 0 {
+		merged.Conference.Start = merged.Days[0].Date
+		merged.Conference.End = merged.Days[len(merged.Days)-1].Date
+		merged.Conference.Days = len(merged.Days)
+	}
+
+	return merged, conflicts
+}
+
+func differingFields(a Event, b Event) []string {
+	fields := make([]string, 0)
+	if a.Title != b.Title {
+		fields = append(fields, "title")
+	}
+	if !a.Start.Equal(b.Start) {
+		fields = append(fields, "start")
+	}
+	if !a.End.Equal(b.End) {
+		fields = append(fields, "end")
+	}
+	if a.Room != b.Room {
+		fields = append(fields, "room")
+	}
+	return fields
+}
+
+func tagSource(schedule *Schedule, label string) {
+	for i := range schedule.Days {
+		for j := range schedule.Days[i].Rooms {
+			for k := range schedule.Days[i].Rooms[j].Events {
+				schedule.Days[i].Rooms[j].Events[k].Source = label
+			}
+		}
+	}
+}
+
+func forEachEvent(schedule *Schedule, f func(event Event)) {
+	for _, day := range schedule.Days {
+		for _, room := range day.Rooms {
+			for _, event := range room.Events {
+				f(event)
+			}
+		}
+	}
+}
+
+func countEvents(schedule *Schedule) int {
+	var count int
+	forEachEvent(schedule, func(Event) {
+		count++
+	})
+	return count
+}
@@ -0,0 +1,104 @@
+package conference
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Speaker is a person along with every event in a schedule they are
+// presenting.
+type Speaker struct {
+	Person Person
+	Events []Event
+}
+
+var ErrSpeakerNotFound = errors.New("speaker not found")
+
+func (s *service) GetSpeakers(conferenceID int32) ([]Speaker, error) {
+	schedule, _, err := s.GetSchedule(conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	speakers := SpeakersInSchedule(schedule)
+	sort.Slice(speakers, func(i, j int) bool {
+		return strings.ToLower(speakers[i].Person.Name) < strings.ToLower(speakers[j].Person.Name)
+	})
+
+	return speakers, nil
+}
+
+func (s *service) GetSpeaker(conferenceID int32, personID int) (*Speaker, error) {
+	schedule, _, err := s.GetSchedule(conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, speaker := range SpeakersInSchedule(schedule) {
+		if speaker.Person.ID == personID {
+			return &speaker, nil
+		}
+	}
+
+	return nil, ErrSpeakerNotFound
+}
+
+// SpeakersInSchedule collects the people presenting in a schedule. A person
+// listed on several events may only have their full details given on some
+// of them, so details are merged from every event they appear on.
+func SpeakersInSchedule(schedule *Schedule) []Speaker {
+	byKey := make(map[string]*Speaker)
+	var order []string
+	for _, day := range schedule.Days {
+		for _, room := range day.Rooms {
+			for _, event := range room.Events {
+				for _, person := range event.Persons {
+					// not every source gives people IDs
+					key := strconv.Itoa(person.ID)
+					if person.ID == 0 {
+						key = "name:" + person.Name
+					}
+
+					speaker, ok := byKey[key]
+					if !ok {
+						speaker = &Speaker{Person: person}
+						byKey[key] = speaker
+						order = append(order, key)
+					}
+					speaker.Person.merge(person)
+					speaker.Events = append(speaker.Events, event)
+				}
+			}
+		}
+	}
+
+	speakers := make([]Speaker, 0, len(order))
+	for _, key := range order {
+		speaker := byKey[key]
+		sort.Slice(speaker.Events, func(i, j int) bool {
+			return speaker.Events[i].Start.Before(speaker.Events[j].Start)
+		})
+		speakers = append(speakers, *speaker)
+	}
+	return speakers
+}
+
+func (dst *Person) merge(src Person) {
+	if dst.GUID == "" {
+		dst.GUID = src.GUID
+	}
+	if dst.Name == "" {
+		dst.Name = src.Name
+	}
+	if dst.Biography == "" {
+		dst.Biography = src.Biography
+	}
+	if dst.Avatar == "" {
+		dst.Avatar = src.Avatar
+	}
+	if len(dst.Links) == 0 {
+		dst.Links = src.Links
+	}
+}
@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE user_profiles (
+    user_id int PRIMARY KEY,
+    display_name text,
+    time_zone text,
+    clock text NOT NULL DEFAULT '24h' CONSTRAINT valid_clock CHECK (clock IN ('12h', '24h')),
+    language text,
+    default_conference_id int,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (default_conference_id) REFERENCES conferences(id) ON DELETE SET NULL
+);
@@ -0,0 +1,4 @@
+-- +goose Up
+ALTER TABLE favourites ADD note text;
+ALTER TABLE favourites ADD priority text CONSTRAINT valid_priority CHECK (priority IN ('must-see', 'maybe'));
+ALTER TABLE favourites ADD attendance text CONSTRAINT valid_attendance CHECK (attendance IN ('attended', 'skipped'));
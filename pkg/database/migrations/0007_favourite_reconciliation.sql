@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE favourites ADD event_title text;
+ALTER TABLE favourites ADD event_start timestamptz;
+ALTER TABLE favourites ADD event_persons text[];
+ALTER TABLE favourites ADD orphaned_at timestamptz;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE agenda_shares (
+    id int GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id int NOT NULL,
+    conference_id int NOT NULL,
+    slug text NOT NULL CONSTRAINT non_blank_slug CHECK(length(slug) > 0),
+    display_name text,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE(user_id, conference_id),
+    UNIQUE(slug),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (conference_id) REFERENCES conferences(id) ON DELETE CASCADE
+);
@@ -0,0 +1,30 @@
+-- +goose Up
+CREATE TABLE user_groups (
+    id int GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name text NOT NULL CONSTRAINT non_blank_name CHECK(length(name) > 0),
+    owner_id int NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE user_group_members (
+    group_id int NOT NULL,
+    user_id int NOT NULL,
+    joined_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (group_id, user_id),
+    FOREIGN KEY (group_id) REFERENCES user_groups(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE user_group_invitations (
+    group_id int NOT NULL,
+    user_id int NOT NULL,
+    invited_by int NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (group_id, user_id),
+    FOREIGN KEY (group_id) REFERENCES user_groups(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (invited_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+ALTER TABLE user_profiles ADD favourites_visibility text NOT NULL DEFAULT 'groups' CONSTRAINT valid_favourites_visibility CHECK (favourites_visibility IN ('private', 'groups'));
@@ -0,0 +1,29 @@
+-- +goose Up
+CREATE TABLE speaker_follows (
+    user_id int NOT NULL,
+    conference_id int NOT NULL,
+    person_id int NOT NULL,
+    person_name text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, conference_id, person_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (conference_id) REFERENCES conferences(id) ON DELETE CASCADE
+);
+
+CREATE INDEX speaker_follows_conference_person ON speaker_follows (conference_id, person_id);
+
+CREATE TABLE notifications (
+    id int GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id int NOT NULL,
+    conference_id int,
+    kind text NOT NULL,
+    title text NOT NULL,
+    body text NOT NULL,
+    event_guid uuid,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    read_at timestamptz,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (conference_id) REFERENCES conferences(id) ON DELETE CASCADE
+);
+
+CREATE INDEX notifications_user ON notifications (user_id, created_at);
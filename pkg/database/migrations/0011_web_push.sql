@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE vapid_keys (
+    id int PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    private_key text NOT NULL,
+    public_key text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE push_subscriptions (
+    id int GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id int NOT NULL,
+    endpoint text NOT NULL UNIQUE,
+    p256dh text NOT NULL,
+    auth text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX push_subscriptions_user ON push_subscriptions (user_id);
+
+CREATE TABLE push_reminders (
+    favourite_id int NOT NULL,
+    event_start timestamptz NOT NULL,
+    sent_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (favourite_id, event_start),
+    FOREIGN KEY (favourite_id) REFERENCES favourites(id) ON DELETE CASCADE
+);
@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE user_profiles
+    ADD COLUMN email text,
+    ADD COLUMN email_verified_at timestamptz,
+    ADD COLUMN email_digest boolean NOT NULL DEFAULT true,
+    ADD COLUMN email_alerts boolean NOT NULL DEFAULT true;
+
+CREATE TABLE email_verifications (
+    user_id int PRIMARY KEY,
+    email text NOT NULL,
+    token_hash text NOT NULL UNIQUE,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE email_digests (
+    user_id int NOT NULL,
+    conference_id int NOT NULL,
+    day date NOT NULL,
+    sent_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, conference_id, day),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (conference_id) REFERENCES conferences(id) ON DELETE CASCADE
+);
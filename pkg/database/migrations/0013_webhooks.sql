@@ -0,0 +1,34 @@
+-- +goose Up
+CREATE TABLE webhooks (
+    id int GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id int NOT NULL,
+    conference_id int NOT NULL,
+    scope text NOT NULL CONSTRAINT valid_scope CHECK (scope IN ('conference', 'user')),
+    url text NOT NULL,
+    secret text NOT NULL,
+    events text[] NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (conference_id) REFERENCES conferences(id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhooks_conference ON webhooks (conference_id);
+
+CREATE TABLE webhook_deliveries (
+    id int GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    webhook_id int NOT NULL,
+    event text NOT NULL,
+    payload jsonb NOT NULL,
+    status text NOT NULL DEFAULT 'pending' CONSTRAINT valid_status CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts int NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL DEFAULT now(),
+    response_status int,
+    response_body text,
+    error text,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    completed_at timestamptz,
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at);
+CREATE INDEX webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
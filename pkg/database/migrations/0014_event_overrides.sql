@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE event_overrides (
+    conference_id int NOT NULL,
+    event_guid uuid NOT NULL,
+    start_time timestamptz,
+    end_time timestamptz,
+    room text,
+    title text,
+    cancelled boolean NOT NULL DEFAULT false,
+    note text,
+    updated_by int,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (conference_id, event_guid),
+    CONSTRAINT valid_times CHECK (start_time IS NULL OR end_time IS NULL OR end_time > start_time),
+    FOREIGN KEY (conference_id) REFERENCES conferences(id) ON DELETE CASCADE,
+    FOREIGN KEY (updated_by) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1,30 @@
+-- +goose Up
+ALTER TABLE conferences ALTER COLUMN url DROP NOT NULL;
+ALTER TABLE conferences ADD time_zone text;
+ALTER TABLE conferences ADD starts_on date;
+ALTER TABLE conferences ADD ends_on date;
+ALTER TABLE conferences ADD CONSTRAINT custom_conference_details CHECK (
+    url IS NOT NULL OR (time_zone IS NOT NULL AND starts_on IS NOT NULL AND ends_on >= starts_on)
+);
+
+CREATE TABLE custom_events (
+    id int GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    conference_id int NOT NULL,
+    guid uuid NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    owner_id int,
+    shared boolean NOT NULL DEFAULT false,
+    title text NOT NULL CONSTRAINT non_blank_title CHECK (length(title) > 0),
+    abstract text NOT NULL DEFAULT '',
+    room text NOT NULL DEFAULT '',
+    url text NOT NULL DEFAULT '',
+    start_time timestamptz NOT NULL,
+    end_time timestamptz NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT valid_times CHECK (end_time > start_time),
+    CONSTRAINT personal_events_owned CHECK (shared OR owner_id IS NOT NULL),
+    FOREIGN KEY (conference_id) REFERENCES conferences(id) ON DELETE CASCADE,
+    FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX custom_events_conference ON custom_events (conference_id, owner_id);
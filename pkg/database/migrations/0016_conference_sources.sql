@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE conference_sources (
+    id int GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    conference_id int NOT NULL,
+    url text NOT NULL,
+    label text NOT NULL CONSTRAINT non_blank_label CHECK (length(label) > 0),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (conference_id, label),
+    UNIQUE (conference_id, url),
+    FOREIGN KEY (conference_id) REFERENCES conferences(id) ON DELETE CASCADE
+);
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE conferences ADD visibility text NOT NULL DEFAULT 'published'
+    CONSTRAINT valid_visibility CHECK (visibility IN ('draft', 'published', 'archived'));
+ALTER TABLE conferences ADD archived_at timestamptz;
+
+CREATE TABLE conference_snapshots (
+    conference_id int PRIMARY KEY,
+    schedule jsonb NOT NULL,
+    fetched_at timestamptz NOT NULL,
+    FOREIGN KEY (conference_id) REFERENCES conferences(id) ON DELETE CASCADE
+);
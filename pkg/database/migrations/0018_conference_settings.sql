@@ -0,0 +1,4 @@
+-- +goose Up
+ALTER TABLE conferences ADD refresh_minutes int NOT NULL DEFAULT 15
+    CONSTRAINT valid_refresh_minutes CHECK (refresh_minutes > 0);
+ALTER TABLE conferences ADD title_override text;
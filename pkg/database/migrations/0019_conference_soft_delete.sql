@@ -0,0 +1,2 @@
+-- +goose Up
+ALTER TABLE conferences ADD deleted_at timestamptz;
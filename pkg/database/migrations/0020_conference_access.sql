@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE conferences ADD access text NOT NULL DEFAULT 'public' CONSTRAINT valid_access CHECK (access IN ('public', 'restricted', 'claim'));
+ALTER TABLE conferences ADD access_claim text;
+ALTER TABLE conferences ADD access_claim_values text[] NOT NULL DEFAULT '{}';
+ALTER TABLE conferences ADD CONSTRAINT claim_access_details CHECK (access <> 'claim' OR (access_claim IS NOT NULL AND cardinality(access_claim_values) > 0));
+
+CREATE TABLE conference_access (
+    id int GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    conference_id int NOT NULL,
+    user_id int,
+    group_id int,
+    CONSTRAINT user_or_group CHECK (num_nonnulls(user_id, group_id) = 1),
+    UNIQUE (conference_id, user_id),
+    UNIQUE (conference_id, group_id),
+    FOREIGN KEY (conference_id) REFERENCES conferences(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (group_id) REFERENCES user_groups(id) ON DELETE CASCADE
+);
+
+CREATE TABLE user_oidc_claims (
+    user_id int PRIMARY KEY,
+    claims jsonb NOT NULL,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,25 @@
+-- name: CreateAgendaShare :one
+INSERT INTO agenda_shares (
+  user_id, conference_id, slug, display_name
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (user_id, conference_id) DO UPDATE SET
+  display_name = EXCLUDED.display_name
+RETURNING *;
+
+-- name: GetAgendaShareForUserConference :one
+SELECT * FROM agenda_shares
+WHERE user_id = $1 AND conference_id = $2 LIMIT 1;
+
+-- name: GetAgendaShareBySlug :one
+SELECT * FROM agenda_shares
+WHERE slug = $1 LIMIT 1;
+
+-- name: GetAgendaSharesForUser :many
+SELECT * FROM agenda_shares
+WHERE user_id = $1;
+
+-- name: DeleteAgendaShareForUserConference :execrows
+DELETE FROM agenda_shares
+WHERE user_id = $1 AND conference_id = $2;
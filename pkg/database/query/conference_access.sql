@@ -0,0 +1,45 @@
+-- name: SetConferenceAccess :one
+UPDATE conferences SET (
+  access, access_claim, access_claim_values
+) = ($2, $3, $4)
+WHERE id = $1
+RETURNING *;
+
+-- name: GetConferenceAccessGrants :many
+SELECT * FROM conference_access
+WHERE conference_id = $1
+ORDER BY id;
+
+-- name: DeleteConferenceAccessGrants :exec
+DELETE FROM conference_access
+WHERE conference_id = $1;
+
+-- name: CreateConferenceAccessGrant :exec
+INSERT INTO conference_access (
+  conference_id, user_id, group_id
+) VALUES (
+  $1, $2, $3
+);
+
+-- name: GetConferenceAccessForUser :one
+SELECT
+  u.admin,
+  EXISTS (
+    SELECT 1 FROM conference_access a
+    LEFT JOIN user_group_members m ON m.group_id = a.group_id
+    WHERE a.conference_id = $1 AND (a.user_id = u.id OR m.user_id = u.id)
+  ) AS granted,
+  c.claims
+FROM users u
+LEFT JOIN user_oidc_claims c ON c.user_id = u.id
+WHERE u.id = $2;
+
+-- name: UpsertUserOIDCClaims :exec
+INSERT INTO user_oidc_claims (
+  user_id, claims
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (user_id) DO UPDATE SET
+  claims = EXCLUDED.claims,
+  updated_at = now();
@@ -0,0 +1,16 @@
+-- name: GetConferenceSources :many
+SELECT * FROM conference_sources
+WHERE conference_id = $1
+ORDER BY id;
+
+-- name: CreateConferenceSource :one
+INSERT INTO conference_sources (
+  conference_id, url, label
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: DeleteConferenceSource :execrows
+DELETE FROM conference_sources
+WHERE id = $1 AND conference_id = $2;
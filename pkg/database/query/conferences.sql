@@ -8,14 +8,75 @@ RETURNING *;
 
 -- name: UpdateConferenceDetails :one
 UPDATE conferences SET (
-  title, venue, city
-) = ($2, $3, $4)
+  title, venue, city, starts_on, ends_on
+) = ($2, $3, $4, $5, $6)
 WHERE id = $1
 RETURNING *;
 
 -- name: GetConferences :many
-SELECT * FROM conferences;
+SELECT * FROM conferences
+WHERE deleted_at IS NULL;
+
+-- name: SoftDeleteConference :one
+UPDATE conferences SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
 
--- name: DeleteConference :exec
+-- name: RestoreConference :one
+UPDATE conferences SET deleted_at = NULL
+WHERE id = $1 AND deleted_at > $2
+RETURNING *;
+
+-- name: GetDeletedConferences :many
+SELECT * FROM conferences
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: PurgeDeletedConferences :many
 DELETE FROM conferences
-WHERE id = $1;
+WHERE deleted_at < $1
+RETURNING id;
+
+-- name: GetConferenceFavouriteImpact :one
+SELECT count(DISTINCT user_id) AS users, count(*) AS favourites FROM favourites
+WHERE conference_id = $1;
+
+-- name: CreateCustomConference :one
+INSERT INTO conferences (
+  title, venue, city, time_zone, starts_on, ends_on
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: SetConferenceVisibility :one
+UPDATE conferences SET (
+  visibility, archived_at
+) = ($2, $3)
+WHERE id = $1
+RETURNING *;
+
+-- name: UpsertConferenceSnapshot :exec
+INSERT INTO conference_snapshots (
+  conference_id, schedule, fetched_at
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (conference_id) DO UPDATE SET
+  schedule = EXCLUDED.schedule,
+  fetched_at = EXCLUDED.fetched_at;
+
+-- name: GetConferenceSnapshot :one
+SELECT * FROM conference_snapshots
+WHERE conference_id = $1;
+
+-- name: DeleteConferenceSnapshot :exec
+DELETE FROM conference_snapshots
+WHERE conference_id = $1;
+
+-- name: UpdateConferenceSettings :one
+UPDATE conferences SET (
+  url, refresh_minutes, title_override
+) = ($2, $3, $4)
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,50 @@
+-- name: GetSharedCustomEvents :many
+SELECT * FROM custom_events
+WHERE conference_id = $1 AND shared
+ORDER BY start_time, id;
+
+-- name: GetPersonalCustomEvents :many
+SELECT * FROM custom_events
+WHERE conference_id = $1 AND owner_id = $2 AND NOT shared
+ORDER BY start_time, id;
+
+-- name: GetCustomEventsForUser :many
+SELECT * FROM custom_events
+WHERE conference_id = $1 AND (shared OR owner_id = $2)
+ORDER BY start_time, id;
+
+-- name: GetCustomEventsOwnedByUser :many
+SELECT * FROM custom_events
+WHERE owner_id = $1
+ORDER BY conference_id, start_time, id;
+
+-- name: GetCustomEvent :one
+SELECT * FROM custom_events
+WHERE id = $1 AND conference_id = $2 LIMIT 1;
+
+-- name: GetCustomEventByGuid :one
+SELECT * FROM custom_events
+WHERE guid = $1 AND conference_id = $2 LIMIT 1;
+
+-- name: CreateCustomEvent :one
+INSERT INTO custom_events (
+  conference_id, owner_id, shared, title, abstract, room, url, start_time, end_time
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: UpdateCustomEvent :one
+UPDATE custom_events SET (
+  title, abstract, room, url, start_time, end_time, updated_at
+) = ($2, $3, $4, $5, $6, $7, now())
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteCustomEvent :exec
+DELETE FROM custom_events
+WHERE id = $1;
+
+-- name: DeletePersonalCustomEventsForUser :exec
+DELETE FROM custom_events
+WHERE owner_id = $1 AND NOT shared;
@@ -0,0 +1,60 @@
+-- name: CreateEmailVerification :one
+INSERT INTO email_verifications (
+  user_id, email, token_hash, expires_at
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (user_id) DO UPDATE SET
+  email = EXCLUDED.email,
+  token_hash = EXCLUDED.token_hash,
+  expires_at = EXCLUDED.expires_at,
+  created_at = now()
+RETURNING *;
+
+-- name: GetEmailVerificationByToken :one
+SELECT * FROM email_verifications
+WHERE token_hash = $1 LIMIT 1;
+
+-- name: DeleteEmailVerification :exec
+DELETE FROM email_verifications
+WHERE user_id = $1;
+
+-- name: SetUserEmail :exec
+INSERT INTO user_profiles (
+  user_id, email, email_verified_at
+) VALUES (
+  $1, $2, now()
+)
+ON CONFLICT (user_id) DO UPDATE SET
+  email = EXCLUDED.email,
+  email_verified_at = EXCLUDED.email_verified_at;
+
+-- name: ClearUserEmail :execrows
+UPDATE user_profiles SET email = NULL, email_verified_at = NULL
+WHERE user_id = $1 AND email IS NOT NULL;
+
+-- name: GetDigestRecipients :many
+SELECT * FROM user_profiles
+WHERE email IS NOT NULL AND email_verified_at IS NOT NULL AND email_digest;
+
+-- name: GetAlertRecipientsForConference :many
+SELECT f.event_guid, f.event_id, p.user_id, p.email, p.time_zone, p.clock FROM favourites f
+JOIN user_profiles p ON p.user_id = f.user_id
+WHERE f.conference_id = $1 AND p.email IS NOT NULL AND p.email_verified_at IS NOT NULL AND p.email_alerts;
+
+-- name: GetEmailDigestsForUser :many
+SELECT * FROM email_digests
+WHERE user_id = $1
+ORDER BY day, conference_id;
+
+-- name: CreateEmailDigest :execrows
+INSERT INTO email_digests (
+  user_id, conference_id, day
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteEmailDigest :exec
+DELETE FROM email_digests
+WHERE user_id = $1 AND conference_id = $2 AND day = $3;
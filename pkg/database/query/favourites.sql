@@ -6,11 +6,19 @@ WHERE user_id = $1 AND conference_id = $2;
 SELECT * FROM favourites
 WHERE user_id = $1;
 
+-- name: GetFavouritesForConference :many
+SELECT * FROM favourites
+WHERE conference_id = $1;
+
+-- name: GetFavouriteForUser :one
+SELECT * FROM favourites
+WHERE id = $1 AND user_id = $2 LIMIT 1;
+
 -- name: CreateFavourite :one
 INSERT INTO favourites (
-  user_id, event_guid, event_id, conference_id
+  user_id, event_guid, event_id, conference_id, event_title, event_start, event_persons, note, priority, attendance
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 )
 RETURNING *;
 
@@ -18,6 +26,44 @@ RETURNING *;
 DELETE FROM favourites
 WHERE id = $1;
 
--- name: DeleteFavouriteByEventDetails :execrows
+-- name: DeleteFavouriteByEventDetails :many
 DELETE FROM favourites
-WHERE (event_guid = $1 OR event_id = $2) AND user_id = $3 AND conference_id = $4;
+WHERE (event_guid = $1 OR ($1 IS NULL AND event_id = $2)) AND user_id = $3 AND conference_id = $4
+RETURNING *;
+
+-- name: DeleteFavouritesForUserConference :many
+DELETE FROM favourites
+WHERE user_id = $1 AND conference_id = $2
+RETURNING *;
+
+-- name: UpdateFavouriteDetails :one
+UPDATE favourites SET (
+  note, priority, attendance
+) = ($3, $4, $5)
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: RebindFavourite :exec
+UPDATE favourites SET (
+  event_guid, event_id, event_title, event_start, event_persons, orphaned_at
+) = ($2, $3, $4, $5, $6, NULL)
+WHERE id = $1;
+
+-- name: MarkFavouriteOrphaned :exec
+UPDATE favourites SET orphaned_at = now()
+WHERE id = $1 AND orphaned_at IS NULL;
+
+-- name: GetFavouriteUsersForConference :many
+SELECT DISTINCT user_id, event_guid, event_id FROM favourites
+WHERE conference_id = $1 AND orphaned_at IS NULL;
+
+-- name: GetCoFavouriteCounts :many
+SELECT f.event_guid, f.event_id, count(DISTINCT f.user_id) AS users FROM favourites f
+WHERE f.conference_id = $1 AND f.user_id <> $2 AND f.orphaned_at IS NULL
+  AND f.user_id IN (
+    SELECT o.user_id FROM favourites o
+    JOIN favourites mine ON mine.conference_id = o.conference_id
+      AND (mine.event_guid = o.event_guid OR (mine.event_guid IS NULL AND mine.event_id = o.event_id))
+    WHERE mine.user_id = $2 AND o.conference_id = $1 AND o.user_id <> $2
+  )
+GROUP BY f.event_guid, f.event_id;
@@ -0,0 +1,25 @@
+-- name: GetEventOverrides :many
+SELECT * FROM event_overrides
+WHERE conference_id = $1
+ORDER BY updated_at;
+
+-- name: UpsertEventOverride :one
+INSERT INTO event_overrides (
+  conference_id, event_guid, start_time, end_time, room, title, cancelled, note, updated_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+ON CONFLICT (conference_id, event_guid) DO UPDATE SET
+  start_time = EXCLUDED.start_time,
+  end_time = EXCLUDED.end_time,
+  room = EXCLUDED.room,
+  title = EXCLUDED.title,
+  cancelled = EXCLUDED.cancelled,
+  note = EXCLUDED.note,
+  updated_by = EXCLUDED.updated_by,
+  updated_at = now()
+RETURNING *;
+
+-- name: DeleteEventOverride :execrows
+DELETE FROM event_overrides
+WHERE conference_id = $1 AND event_guid = $2;
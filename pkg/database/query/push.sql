@@ -0,0 +1,48 @@
+-- name: GetVapidKeys :one
+SELECT * FROM vapid_keys
+WHERE id = 1;
+
+-- name: CreateVapidKeys :one
+INSERT INTO vapid_keys (
+  private_key, public_key
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (id) DO UPDATE SET id = vapid_keys.id
+RETURNING *;
+
+-- name: UpsertPushSubscription :one
+INSERT INTO push_subscriptions (
+  user_id, endpoint, p256dh, auth
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (endpoint) DO UPDATE SET
+  user_id = EXCLUDED.user_id,
+  p256dh = EXCLUDED.p256dh,
+  auth = EXCLUDED.auth
+RETURNING *;
+
+-- name: GetPushSubscriptionsForUser :many
+SELECT * FROM push_subscriptions
+WHERE user_id = $1
+ORDER BY id;
+
+-- name: GetUsersWithPushSubscriptions :many
+SELECT DISTINCT user_id FROM push_subscriptions;
+
+-- name: DeletePushSubscription :execrows
+DELETE FROM push_subscriptions
+WHERE user_id = $1 AND endpoint = $2;
+
+-- name: DeletePushSubscriptionByEndpoint :exec
+DELETE FROM push_subscriptions
+WHERE endpoint = $1;
+
+-- name: CreatePushReminder :execrows
+INSERT INTO push_reminders (
+  favourite_id, event_start
+) VALUES (
+  $1, $2
+)
+ON CONFLICT DO NOTHING;
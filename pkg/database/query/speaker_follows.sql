@@ -0,0 +1,22 @@
+-- name: CreateSpeakerFollow :one
+INSERT INTO speaker_follows (
+  user_id, conference_id, person_id, person_name
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (user_id, conference_id, person_id) DO UPDATE SET
+  person_name = EXCLUDED.person_name
+RETURNING *;
+
+-- name: GetSpeakerFollowsForUser :many
+SELECT * FROM speaker_follows
+WHERE user_id = $1
+ORDER BY conference_id, person_name;
+
+-- name: GetSpeakerFollowsForConference :many
+SELECT * FROM speaker_follows
+WHERE conference_id = $1;
+
+-- name: DeleteSpeakerFollow :execrows
+DELETE FROM speaker_follows
+WHERE user_id = $1 AND conference_id = $2 AND person_id = $3;
@@ -0,0 +1,81 @@
+-- name: CreateUserGroup :one
+INSERT INTO user_groups (
+  name, owner_id
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: GetUserGroup :one
+SELECT * FROM user_groups
+WHERE id = $1 LIMIT 1;
+
+-- name: GetUserGroupsForUser :many
+SELECT g.* FROM user_groups g
+JOIN user_group_members m ON m.group_id = g.id
+WHERE m.user_id = $1
+ORDER BY g.name;
+
+-- name: UpdateUserGroupOwner :exec
+UPDATE user_groups SET owner_id = $2
+WHERE id = $1;
+
+-- name: DeleteUserGroup :exec
+DELETE FROM user_groups
+WHERE id = $1;
+
+-- name: AddUserGroupMember :exec
+INSERT INTO user_group_members (
+  group_id, user_id
+) VALUES (
+  $1, $2
+)
+ON CONFLICT DO NOTHING;
+
+-- name: GetUserGroupMember :one
+SELECT * FROM user_group_members
+WHERE group_id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: GetOldestUserGroupMember :one
+SELECT * FROM user_group_members
+WHERE group_id = $1
+ORDER BY joined_at LIMIT 1;
+
+-- name: GetUserGroupMembers :many
+SELECT u.id, u.username, p.display_name, m.joined_at FROM user_group_members m
+JOIN users u ON u.id = m.user_id
+LEFT JOIN user_profiles p ON p.user_id = m.user_id
+WHERE m.group_id = $1
+ORDER BY m.joined_at;
+
+-- name: DeleteUserGroupMember :execrows
+DELETE FROM user_group_members
+WHERE group_id = $1 AND user_id = $2;
+
+-- name: CreateUserGroupInvitation :exec
+INSERT INTO user_group_invitations (
+  group_id, user_id, invited_by
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT DO NOTHING;
+
+-- name: GetUserGroupInvitationsForUser :many
+SELECT g.id, g.name, u.username AS invited_by, i.created_at FROM user_group_invitations i
+JOIN user_groups g ON g.id = i.group_id
+JOIN users u ON u.id = i.invited_by
+WHERE i.user_id = $1
+ORDER BY i.created_at;
+
+-- name: DeleteUserGroupInvitation :execrows
+DELETE FROM user_group_invitations
+WHERE group_id = $1 AND user_id = $2;
+
+-- name: GetUserGroupFavourites :many
+SELECT f.event_guid, f.event_id, u.id AS user_id, u.username, p.display_name FROM user_group_members m
+JOIN users u ON u.id = m.user_id
+JOIN favourites f ON f.user_id = m.user_id
+LEFT JOIN user_profiles p ON p.user_id = m.user_id
+WHERE m.group_id = $1 AND f.conference_id = $2 AND f.orphaned_at IS NULL
+  AND COALESCE(p.favourites_visibility, 'groups') = 'groups'
+ORDER BY u.username;
@@ -21,3 +21,24 @@ RETURNING *;
 -- name: DeleteUser :exec
 DELETE FROM users
 WHERE id = $1;
+
+-- name: GetUserProfile :one
+SELECT * FROM user_profiles
+WHERE user_id = $1 LIMIT 1;
+
+-- name: UpsertUserProfile :one
+INSERT INTO user_profiles (
+  user_id, display_name, time_zone, clock, language, default_conference_id, favourites_visibility, email_digest, email_alerts
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+ON CONFLICT (user_id) DO UPDATE SET
+  display_name = EXCLUDED.display_name,
+  time_zone = EXCLUDED.time_zone,
+  clock = EXCLUDED.clock,
+  language = EXCLUDED.language,
+  default_conference_id = EXCLUDED.default_conference_id,
+  favourites_visibility = EXCLUDED.favourites_visibility,
+  email_digest = EXCLUDED.email_digest,
+  email_alerts = EXCLUDED.email_alerts
+RETURNING *;
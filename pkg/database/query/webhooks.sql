@@ -0,0 +1,65 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (
+  user_id, conference_id, scope, url, secret, events
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks
+WHERE id = $1 LIMIT 1;
+
+-- name: GetConferenceWebhooks :many
+SELECT * FROM webhooks
+WHERE conference_id = $1 AND scope = 'conference'
+ORDER BY id;
+
+-- name: GetUserWebhooks :many
+SELECT * FROM webhooks
+WHERE user_id = $1 AND scope = 'user'
+ORDER BY id;
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks
+WHERE id = $1;
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+  webhook_id, event, payload
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1 AND webhook_id = $2 LIMIT 1;
+
+-- name: GetWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE webhook_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2;
+
+-- name: ClaimDueWebhookDeliveries :many
+UPDATE webhook_deliveries SET next_attempt_at = now() + interval '5 minutes'
+WHERE id IN (
+  SELECT d.id FROM webhook_deliveries d
+  WHERE d.status = 'pending' AND d.next_attempt_at <= now()
+  ORDER BY d.next_attempt_at
+  LIMIT $1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: RecordWebhookDeliveryAttempt :exec
+UPDATE webhook_deliveries SET
+  status = $2,
+  attempts = attempts + 1,
+  next_attempt_at = $3,
+  response_status = $4,
+  response_body = $5,
+  error = $6,
+  completed_at = CASE WHEN $2 = 'pending' THEN NULL ELSE now() END
+WHERE id = $1;
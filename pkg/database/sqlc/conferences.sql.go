@@ -17,11 +17,11 @@ INSERT INTO conferences (
 ) VALUES (
   $1, $2, $3, $4
 )
-RETURNING id, url, title, venue, city
+RETURNING id, url, title, venue, city, time_zone, starts_on, ends_on, visibility, archived_at, refresh_minutes, title_override, deleted_at, access, access_claim, access_claim_values
 `
 
 type CreateConferenceParams struct {
-	Url   string      `json:"url"`
+	Url   pgtype.Text `json:"url"`
 	Title pgtype.Text `json:"title"`
 	Venue pgtype.Text `json:"venue"`
 	City  pgtype.Text `json:"city"`
@@ -41,22 +41,119 @@ func (q *Queries) CreateConference(ctx context.Context, arg CreateConferencePara
 		&i.Title,
 		&i.Venue,
 		&i.City,
+		&i.TimeZone,
+		&i.StartsOn,
+		&i.EndsOn,
+		&i.Visibility,
+		&i.ArchivedAt,
+		&i.RefreshMinutes,
+		&i.TitleOverride,
+		&i.DeletedAt,
+		&i.Access,
+		&i.AccessClaim,
+		&i.AccessClaimValues,
 	)
 	return i, err
 }
 
-const deleteConference = `-- name: DeleteConference :exec
-DELETE FROM conferences
-WHERE id = $1
+const createCustomConference = `-- name: CreateCustomConference :one
+INSERT INTO conferences (
+  title, venue, city, time_zone, starts_on, ends_on
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING id, url, title, venue, city, time_zone, starts_on, ends_on, visibility, archived_at, refresh_minutes, title_override, deleted_at, access, access_claim, access_claim_values
 `
 
-func (q *Queries) DeleteConference(ctx context.Context, id int32) error {
-	_, err := q.db.Exec(ctx, deleteConference, id)
+type CreateCustomConferenceParams struct {
+	Title    pgtype.Text `json:"title"`
+	Venue    pgtype.Text `json:"venue"`
+	City     pgtype.Text `json:"city"`
+	TimeZone pgtype.Text `json:"time_zone"`
+	StartsOn pgtype.Date `json:"starts_on"`
+	EndsOn   pgtype.Date `json:"ends_on"`
+}
+
+func (q *Queries) CreateCustomConference(ctx context.Context, arg CreateCustomConferenceParams) (Conference, error) {
+	row := q.db.QueryRow(ctx, createCustomConference,
+		arg.Title,
+		arg.Venue,
+		arg.City,
+		arg.TimeZone,
+		arg.StartsOn,
+		arg.EndsOn,
+	)
+	var i Conference
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Title,
+		&i.Venue,
+		&i.City,
+		&i.TimeZone,
+		&i.StartsOn,
+		&i.EndsOn,
+		&i.Visibility,
+		&i.ArchivedAt,
+		&i.RefreshMinutes,
+		&i.TitleOverride,
+		&i.DeletedAt,
+		&i.Access,
+		&i.AccessClaim,
+		&i.AccessClaimValues,
+	)
+	return i, err
+}
+
+const deleteConferenceSnapshot = `-- name: DeleteConferenceSnapshot :exec
+DELETE FROM conference_snapshots
+WHERE conference_id = $1
+`
+
+func (q *Queries) DeleteConferenceSnapshot(ctx context.Context, conferenceID int32) error {
+	_, err := q.db.Exec(ctx, deleteConferenceSnapshot, conferenceID)
 	return err
 }
 
+const getConferenceFavouriteImpact = `-- name: GetConferenceFavouriteImpact :one
+SELECT count(DISTINCT user_id) AS users, count(*) AS favourites FROM favourites
+WHERE conference_id = $1
+`
+
+type GetConferenceFavouriteImpactRow struct {
+	Users      int64 `json:"users"`
+	Favourites int64 `json:"favourites"`
+}
+
+func (q *Queries) GetConferenceFavouriteImpact(ctx context.Context, conferenceID int32) (GetConferenceFavouriteImpactRow, error) {
+	row := q.db.QueryRow(ctx, getConferenceFavouriteImpact, conferenceID)
+	var i GetConferenceFavouriteImpactRow
+	err := row.Scan(
+		&i.Users,
+		&i.Favourites,
+	)
+	return i, err
+}
+
+const getConferenceSnapshot = `-- name: GetConferenceSnapshot :one
+SELECT conference_id, schedule, fetched_at FROM conference_snapshots
+WHERE conference_id = $1
+`
+
+func (q *Queries) GetConferenceSnapshot(ctx context.Context, conferenceID int32) (ConferenceSnapshot, error) {
+	row := q.db.QueryRow(ctx, getConferenceSnapshot, conferenceID)
+	var i ConferenceSnapshot
+	err := row.Scan(
+		&i.ConferenceID,
+		&i.Schedule,
+		&i.FetchedAt,
+	)
+	return i, err
+}
+
 const getConferences = `-- name: GetConferences :many
-SELECT id, url, title, venue, city FROM conferences
+SELECT id, url, title, venue, city, time_zone, starts_on, ends_on, visibility, archived_at, refresh_minutes, title_override, deleted_at, access, access_claim, access_claim_values FROM conferences
+WHERE deleted_at IS NULL
 `
 
 func (q *Queries) GetConferences(ctx context.Context) ([]Conference, error) {
@@ -74,6 +171,17 @@ func (q *Queries) GetConferences(ctx context.Context) ([]Conference, error) {
 			&i.Title,
 			&i.Venue,
 			&i.City,
+			&i.TimeZone,
+			&i.StartsOn,
+			&i.EndsOn,
+			&i.Visibility,
+			&i.ArchivedAt,
+			&i.RefreshMinutes,
+			&i.TitleOverride,
+			&i.DeletedAt,
+			&i.Access,
+			&i.AccessClaim,
+			&i.AccessClaimValues,
 		); err != nil {
 			return nil, err
 		}
@@ -85,19 +193,193 @@ func (q *Queries) GetConferences(ctx context.Context) ([]Conference, error) {
 	return items, nil
 }
 
+const getDeletedConferences = `-- name: GetDeletedConferences :many
+SELECT id, url, title, venue, city, time_zone, starts_on, ends_on, visibility, archived_at, refresh_minutes, title_override, deleted_at, access, access_claim, access_claim_values FROM conferences
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+`
+
+func (q *Queries) GetDeletedConferences(ctx context.Context) ([]Conference, error) {
+	rows, err := q.db.Query(ctx, getDeletedConferences)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Conference
+	for rows.Next() {
+		var i Conference
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Venue,
+			&i.City,
+			&i.TimeZone,
+			&i.StartsOn,
+			&i.EndsOn,
+			&i.Visibility,
+			&i.ArchivedAt,
+			&i.RefreshMinutes,
+			&i.TitleOverride,
+			&i.DeletedAt,
+			&i.Access,
+			&i.AccessClaim,
+			&i.AccessClaimValues,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedConferences = `-- name: PurgeDeletedConferences :many
+DELETE FROM conferences
+WHERE deleted_at < $1
+RETURNING id
+`
+
+func (q *Queries) PurgeDeletedConferences(ctx context.Context, deletedAt pgtype.Timestamptz) ([]int32, error) {
+	rows, err := q.db.Query(ctx, purgeDeletedConferences, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var column_1 int32
+		if err := rows.Scan(&column_1); err != nil {
+			return nil, err
+		}
+		items = append(items, column_1)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreConference = `-- name: RestoreConference :one
+UPDATE conferences SET deleted_at = NULL
+WHERE id = $1 AND deleted_at > $2
+RETURNING id, url, title, venue, city, time_zone, starts_on, ends_on, visibility, archived_at, refresh_minutes, title_override, deleted_at, access, access_claim, access_claim_values
+`
+
+type RestoreConferenceParams struct {
+	ID        int32              `json:"id"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (q *Queries) RestoreConference(ctx context.Context, arg RestoreConferenceParams) (Conference, error) {
+	row := q.db.QueryRow(ctx, restoreConference, arg.ID, arg.DeletedAt)
+	var i Conference
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Title,
+		&i.Venue,
+		&i.City,
+		&i.TimeZone,
+		&i.StartsOn,
+		&i.EndsOn,
+		&i.Visibility,
+		&i.ArchivedAt,
+		&i.RefreshMinutes,
+		&i.TitleOverride,
+		&i.DeletedAt,
+		&i.Access,
+		&i.AccessClaim,
+		&i.AccessClaimValues,
+	)
+	return i, err
+}
+
+const setConferenceVisibility = `-- name: SetConferenceVisibility :one
+UPDATE conferences SET (
+  visibility, archived_at
+) = ($2, $3)
+WHERE id = $1
+RETURNING id, url, title, venue, city, time_zone, starts_on, ends_on, visibility, archived_at, refresh_minutes, title_override, deleted_at, access, access_claim, access_claim_values
+`
+
+type SetConferenceVisibilityParams struct {
+	ID         int32              `json:"id"`
+	Visibility string             `json:"visibility"`
+	ArchivedAt pgtype.Timestamptz `json:"archived_at"`
+}
+
+func (q *Queries) SetConferenceVisibility(ctx context.Context, arg SetConferenceVisibilityParams) (Conference, error) {
+	row := q.db.QueryRow(ctx, setConferenceVisibility, arg.ID, arg.Visibility, arg.ArchivedAt)
+	var i Conference
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Title,
+		&i.Venue,
+		&i.City,
+		&i.TimeZone,
+		&i.StartsOn,
+		&i.EndsOn,
+		&i.Visibility,
+		&i.ArchivedAt,
+		&i.RefreshMinutes,
+		&i.TitleOverride,
+		&i.DeletedAt,
+		&i.Access,
+		&i.AccessClaim,
+		&i.AccessClaimValues,
+	)
+	return i, err
+}
+
+const softDeleteConference = `-- name: SoftDeleteConference :one
+UPDATE conferences SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, url, title, venue, city, time_zone, starts_on, ends_on, visibility, archived_at, refresh_minutes, title_override, deleted_at, access, access_claim, access_claim_values
+`
+
+func (q *Queries) SoftDeleteConference(ctx context.Context, id int32) (Conference, error) {
+	row := q.db.QueryRow(ctx, softDeleteConference, id)
+	var i Conference
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Title,
+		&i.Venue,
+		&i.City,
+		&i.TimeZone,
+		&i.StartsOn,
+		&i.EndsOn,
+		&i.Visibility,
+		&i.ArchivedAt,
+		&i.RefreshMinutes,
+		&i.TitleOverride,
+		&i.DeletedAt,
+		&i.Access,
+		&i.AccessClaim,
+		&i.AccessClaimValues,
+	)
+	return i, err
+}
+
 const updateConferenceDetails = `-- name: UpdateConferenceDetails :one
 UPDATE conferences SET (
-  title, venue, city
-) = ($2, $3, $4)
+  title, venue, city, starts_on, ends_on
+) = ($2, $3, $4, $5, $6)
 WHERE id = $1
-RETURNING id, url, title, venue, city
+RETURNING id, url, title, venue, city, time_zone, starts_on, ends_on, visibility, archived_at, refresh_minutes, title_override, deleted_at, access, access_claim, access_claim_values
 `
 
 type UpdateConferenceDetailsParams struct {
-	ID    int32       `json:"id"`
-	Title pgtype.Text `json:"title"`
-	Venue pgtype.Text `json:"venue"`
-	City  pgtype.Text `json:"city"`
+	ID       int32       `json:"id"`
+	Title    pgtype.Text `json:"title"`
+	Venue    pgtype.Text `json:"venue"`
+	City     pgtype.Text `json:"city"`
+	StartsOn pgtype.Date `json:"starts_on"`
+	EndsOn   pgtype.Date `json:"ends_on"`
 }
 
 func (q *Queries) UpdateConferenceDetails(ctx context.Context, arg UpdateConferenceDetailsParams) (Conference, error) {
@@ -106,6 +388,52 @@ func (q *Queries) UpdateConferenceDetails(ctx context.Context, arg UpdateConfere
 		arg.Title,
 		arg.Venue,
 		arg.City,
+		arg.StartsOn,
+		arg.EndsOn,
+	)
+	var i Conference
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Title,
+		&i.Venue,
+		&i.City,
+		&i.TimeZone,
+		&i.StartsOn,
+		&i.EndsOn,
+		&i.Visibility,
+		&i.ArchivedAt,
+		&i.RefreshMinutes,
+		&i.TitleOverride,
+		&i.DeletedAt,
+		&i.Access,
+		&i.AccessClaim,
+		&i.AccessClaimValues,
+	)
+	return i, err
+}
+
+const updateConferenceSettings = `-- name: UpdateConferenceSettings :one
+UPDATE conferences SET (
+  url, refresh_minutes, title_override
+) = ($2, $3, $4)
+WHERE id = $1
+RETURNING id, url, title, venue, city, time_zone, starts_on, ends_on, visibility, archived_at, refresh_minutes, title_override, deleted_at, access, access_claim, access_claim_values
+`
+
+type UpdateConferenceSettingsParams struct {
+	ID             int32       `json:"id"`
+	Url            pgtype.Text `json:"url"`
+	RefreshMinutes int32       `json:"refresh_minutes"`
+	TitleOverride  pgtype.Text `json:"title_override"`
+}
+
+func (q *Queries) UpdateConferenceSettings(ctx context.Context, arg UpdateConferenceSettingsParams) (Conference, error) {
+	row := q.db.QueryRow(ctx, updateConferenceSettings,
+		arg.ID,
+		arg.Url,
+		arg.RefreshMinutes,
+		arg.TitleOverride,
 	)
 	var i Conference
 	err := row.Scan(
@@ -114,6 +442,39 @@ func (q *Queries) UpdateConferenceDetails(ctx context.Context, arg UpdateConfere
 		&i.Title,
 		&i.Venue,
 		&i.City,
+		&i.TimeZone,
+		&i.StartsOn,
+		&i.EndsOn,
+		&i.Visibility,
+		&i.ArchivedAt,
+		&i.RefreshMinutes,
+		&i.TitleOverride,
+		&i.DeletedAt,
+		&i.Access,
+		&i.AccessClaim,
+		&i.AccessClaimValues,
 	)
 	return i, err
 }
+
+const upsertConferenceSnapshot = `-- name: UpsertConferenceSnapshot :exec
+INSERT INTO conference_snapshots (
+  conference_id, schedule, fetched_at
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (conference_id) DO UPDATE SET
+  schedule = EXCLUDED.schedule,
+  fetched_at = EXCLUDED.fetched_at
+`
+
+type UpsertConferenceSnapshotParams struct {
+	ConferenceID int32              `json:"conference_id"`
+	Schedule     []byte             `json:"schedule"`
+	FetchedAt    pgtype.Timestamptz `json:"fetched_at"`
+}
+
+func (q *Queries) UpsertConferenceSnapshot(ctx context.Context, arg UpsertConferenceSnapshotParams) error {
+	_, err := q.db.Exec(ctx, upsertConferenceSnapshot, arg.ConferenceID, arg.Schedule, arg.FetchedAt)
+	return err
+}
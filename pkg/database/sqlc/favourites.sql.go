@@ -13,18 +13,24 @@ import (
 
 const createFavourite = `-- name: CreateFavourite :one
 INSERT INTO favourites (
-  user_id, event_guid, event_id, conference_id
+  user_id, event_guid, event_id, conference_id, event_title, event_start, event_persons, note, priority, attendance
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 )
-RETURNING id, user_id, event_guid, event_id, conference_id
+RETURNING id, user_id, event_guid, event_id, conference_id, note, priority, attendance, event_title, event_start, event_persons, orphaned_at
 `
 
 type CreateFavouriteParams struct {
-	UserID       int32       `json:"user_id"`
-	EventGuid    pgtype.UUID `json:"event_guid"`
-	EventID      pgtype.Int4 `json:"event_id"`
-	ConferenceID int32       `json:"conference_id"`
+	UserID       int32              `json:"user_id"`
+	EventGuid    pgtype.UUID        `json:"event_guid"`
+	EventID      pgtype.Int4        `json:"event_id"`
+	ConferenceID int32              `json:"conference_id"`
+	EventTitle   pgtype.Text        `json:"event_title"`
+	EventStart   pgtype.Timestamptz `json:"event_start"`
+	EventPersons []string           `json:"event_persons"`
+	Note         pgtype.Text        `json:"note"`
+	Priority     pgtype.Text        `json:"priority"`
+	Attendance   pgtype.Text        `json:"attendance"`
 }
 
 func (q *Queries) CreateFavourite(ctx context.Context, arg CreateFavouriteParams) (Favourite, error) {
@@ -33,6 +39,12 @@ func (q *Queries) CreateFavourite(ctx context.Context, arg CreateFavouriteParams
 		arg.EventGuid,
 		arg.EventID,
 		arg.ConferenceID,
+		arg.EventTitle,
+		arg.EventStart,
+		arg.EventPersons,
+		arg.Note,
+		arg.Priority,
+		arg.Attendance,
 	)
 	var i Favourite
 	err := row.Scan(
@@ -41,6 +53,13 @@ func (q *Queries) CreateFavourite(ctx context.Context, arg CreateFavouriteParams
 		&i.EventGuid,
 		&i.EventID,
 		&i.ConferenceID,
+		&i.Note,
+		&i.Priority,
+		&i.Attendance,
+		&i.EventTitle,
+		&i.EventStart,
+		&i.EventPersons,
+		&i.OrphanedAt,
 	)
 	return i, err
 }
@@ -55,9 +74,10 @@ func (q *Queries) DeleteFavourite(ctx context.Context, id int32) error {
 	return err
 }
 
-const deleteFavouriteByEventDetails = `-- name: DeleteFavouriteByEventDetails :execrows
+const deleteFavouriteByEventDetails = `-- name: DeleteFavouriteByEventDetails :many
 DELETE FROM favourites
-WHERE (event_guid = $1 OR event_id = $2) AND user_id = $3 AND conference_id = $4
+WHERE (event_guid = $1 OR ($1 IS NULL AND event_id = $2)) AND user_id = $3 AND conference_id = $4
+RETURNING id, user_id, event_guid, event_id, conference_id, note, priority, attendance, event_title, event_start, event_persons, orphaned_at
 `
 
 type DeleteFavouriteByEventDetailsParams struct {
@@ -67,21 +87,240 @@ type DeleteFavouriteByEventDetailsParams struct {
 	ConferenceID int32       `json:"conference_id"`
 }
 
-func (q *Queries) DeleteFavouriteByEventDetails(ctx context.Context, arg DeleteFavouriteByEventDetailsParams) (int64, error) {
-	result, err := q.db.Exec(ctx, deleteFavouriteByEventDetails,
+func (q *Queries) DeleteFavouriteByEventDetails(ctx context.Context, arg DeleteFavouriteByEventDetailsParams) ([]Favourite, error) {
+	rows, err := q.db.Query(ctx, deleteFavouriteByEventDetails,
 		arg.EventGuid,
 		arg.EventID,
 		arg.UserID,
 		arg.ConferenceID,
 	)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Favourite
+	for rows.Next() {
+		var i Favourite
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.EventGuid,
+			&i.EventID,
+			&i.ConferenceID,
+			&i.Note,
+			&i.Priority,
+			&i.Attendance,
+			&i.EventTitle,
+			&i.EventStart,
+			&i.EventPersons,
+			&i.OrphanedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-	return result.RowsAffected(), nil
+	return items, nil
+}
+
+const deleteFavouritesForUserConference = `-- name: DeleteFavouritesForUserConference :many
+DELETE FROM favourites
+WHERE user_id = $1 AND conference_id = $2
+RETURNING id, user_id, event_guid, event_id, conference_id, note, priority, attendance, event_title, event_start, event_persons, orphaned_at
+`
+
+type DeleteFavouritesForUserConferenceParams struct {
+	UserID       int32 `json:"user_id"`
+	ConferenceID int32 `json:"conference_id"`
+}
+
+func (q *Queries) DeleteFavouritesForUserConference(ctx context.Context, arg DeleteFavouritesForUserConferenceParams) ([]Favourite, error) {
+	rows, err := q.db.Query(ctx, deleteFavouritesForUserConference, arg.UserID, arg.ConferenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Favourite
+	for rows.Next() {
+		var i Favourite
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.EventGuid,
+			&i.EventID,
+			&i.ConferenceID,
+			&i.Note,
+			&i.Priority,
+			&i.Attendance,
+			&i.EventTitle,
+			&i.EventStart,
+			&i.EventPersons,
+			&i.OrphanedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCoFavouriteCounts = `-- name: GetCoFavouriteCounts :many
+SELECT f.event_guid, f.event_id, count(DISTINCT f.user_id) AS users FROM favourites f
+WHERE f.conference_id = $1 AND f.user_id <> $2 AND f.orphaned_at IS NULL
+  AND f.user_id IN (
+    SELECT o.user_id FROM favourites o
+    JOIN favourites mine ON mine.conference_id = o.conference_id
+      AND (mine.event_guid = o.event_guid OR (mine.event_guid IS NULL AND mine.event_id = o.event_id))
+    WHERE mine.user_id = $2 AND o.conference_id = $1 AND o.user_id <> $2
+  )
+GROUP BY f.event_guid, f.event_id
+`
+
+type GetCoFavouriteCountsParams struct {
+	ConferenceID int32 `json:"conference_id"`
+	UserID       int32 `json:"user_id"`
+}
+
+type GetCoFavouriteCountsRow struct {
+	EventGuid pgtype.UUID `json:"event_guid"`
+	EventID   pgtype.Int4 `json:"event_id"`
+	Users     int64       `json:"users"`
+}
+
+func (q *Queries) GetCoFavouriteCounts(ctx context.Context, arg GetCoFavouriteCountsParams) ([]GetCoFavouriteCountsRow, error) {
+	rows, err := q.db.Query(ctx, getCoFavouriteCounts, arg.ConferenceID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCoFavouriteCountsRow
+	for rows.Next() {
+		var i GetCoFavouriteCountsRow
+		if err := rows.Scan(
+			&i.EventGuid,
+			&i.EventID,
+			&i.Users,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFavouriteForUser = `-- name: GetFavouriteForUser :one
+SELECT id, user_id, event_guid, event_id, conference_id, note, priority, attendance, event_title, event_start, event_persons, orphaned_at FROM favourites
+WHERE id = $1 AND user_id = $2 LIMIT 1
+`
+
+type GetFavouriteForUserParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+func (q *Queries) GetFavouriteForUser(ctx context.Context, arg GetFavouriteForUserParams) (Favourite, error) {
+	row := q.db.QueryRow(ctx, getFavouriteForUser, arg.ID, arg.UserID)
+	var i Favourite
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.EventGuid,
+		&i.EventID,
+		&i.ConferenceID,
+		&i.Note,
+		&i.Priority,
+		&i.Attendance,
+		&i.EventTitle,
+		&i.EventStart,
+		&i.EventPersons,
+		&i.OrphanedAt,
+	)
+	return i, err
+}
+
+const getFavouriteUsersForConference = `-- name: GetFavouriteUsersForConference :many
+SELECT DISTINCT user_id, event_guid, event_id FROM favourites
+WHERE conference_id = $1 AND orphaned_at IS NULL
+`
+
+type GetFavouriteUsersForConferenceRow struct {
+	UserID    int32       `json:"user_id"`
+	EventGuid pgtype.UUID `json:"event_guid"`
+	EventID   pgtype.Int4 `json:"event_id"`
+}
+
+func (q *Queries) GetFavouriteUsersForConference(ctx context.Context, conferenceID int32) ([]GetFavouriteUsersForConferenceRow, error) {
+	rows, err := q.db.Query(ctx, getFavouriteUsersForConference, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFavouriteUsersForConferenceRow
+	for rows.Next() {
+		var i GetFavouriteUsersForConferenceRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.EventGuid,
+			&i.EventID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFavouritesForConference = `-- name: GetFavouritesForConference :many
+SELECT id, user_id, event_guid, event_id, conference_id, note, priority, attendance, event_title, event_start, event_persons, orphaned_at FROM favourites
+WHERE conference_id = $1
+`
+
+func (q *Queries) GetFavouritesForConference(ctx context.Context, conferenceID int32) ([]Favourite, error) {
+	rows, err := q.db.Query(ctx, getFavouritesForConference, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Favourite
+	for rows.Next() {
+		var i Favourite
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.EventGuid,
+			&i.EventID,
+			&i.ConferenceID,
+			&i.Note,
+			&i.Priority,
+			&i.Attendance,
+			&i.EventTitle,
+			&i.EventStart,
+			&i.EventPersons,
+			&i.OrphanedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getFavouritesForUser = `-- name: GetFavouritesForUser :many
-SELECT id, user_id, event_guid, event_id, conference_id FROM favourites
+SELECT id, user_id, event_guid, event_id, conference_id, note, priority, attendance, event_title, event_start, event_persons, orphaned_at FROM favourites
 WHERE user_id = $1
 `
 
@@ -100,6 +339,13 @@ func (q *Queries) GetFavouritesForUser(ctx context.Context, userID int32) ([]Fav
 			&i.EventGuid,
 			&i.EventID,
 			&i.ConferenceID,
+			&i.Note,
+			&i.Priority,
+			&i.Attendance,
+			&i.EventTitle,
+			&i.EventStart,
+			&i.EventPersons,
+			&i.OrphanedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -112,7 +358,7 @@ func (q *Queries) GetFavouritesForUser(ctx context.Context, userID int32) ([]Fav
 }
 
 const getFavouritesForUserConference = `-- name: GetFavouritesForUserConference :many
-SELECT id, user_id, event_guid, event_id, conference_id FROM favourites
+SELECT id, user_id, event_guid, event_id, conference_id, note, priority, attendance, event_title, event_start, event_persons, orphaned_at FROM favourites
 WHERE user_id = $1 AND conference_id = $2
 `
 
@@ -136,6 +382,13 @@ func (q *Queries) GetFavouritesForUserConference(ctx context.Context, arg GetFav
 			&i.EventGuid,
 			&i.EventID,
 			&i.ConferenceID,
+			&i.Note,
+			&i.Priority,
+			&i.Attendance,
+			&i.EventTitle,
+			&i.EventStart,
+			&i.EventPersons,
+			&i.OrphanedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -146,3 +399,83 @@ func (q *Queries) GetFavouritesForUserConference(ctx context.Context, arg GetFav
 	}
 	return items, nil
 }
+
+const markFavouriteOrphaned = `-- name: MarkFavouriteOrphaned :exec
+UPDATE favourites SET orphaned_at = now()
+WHERE id = $1 AND orphaned_at IS NULL
+`
+
+func (q *Queries) MarkFavouriteOrphaned(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, markFavouriteOrphaned, id)
+	return err
+}
+
+const rebindFavourite = `-- name: RebindFavourite :exec
+UPDATE favourites SET (
+  event_guid, event_id, event_title, event_start, event_persons, orphaned_at
+) = ($2, $3, $4, $5, $6, NULL)
+WHERE id = $1
+`
+
+type RebindFavouriteParams struct {
+	ID           int32              `json:"id"`
+	EventGuid    pgtype.UUID        `json:"event_guid"`
+	EventID      pgtype.Int4        `json:"event_id"`
+	EventTitle   pgtype.Text        `json:"event_title"`
+	EventStart   pgtype.Timestamptz `json:"event_start"`
+	EventPersons []string           `json:"event_persons"`
+}
+
+func (q *Queries) RebindFavourite(ctx context.Context, arg RebindFavouriteParams) error {
+	_, err := q.db.Exec(ctx, rebindFavourite,
+		arg.ID,
+		arg.EventGuid,
+		arg.EventID,
+		arg.EventTitle,
+		arg.EventStart,
+		arg.EventPersons,
+	)
+	return err
+}
+
+const updateFavouriteDetails = `-- name: UpdateFavouriteDetails :one
+UPDATE favourites SET (
+  note, priority, attendance
+) = ($3, $4, $5)
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, event_guid, event_id, conference_id, note, priority, attendance, event_title, event_start, event_persons, orphaned_at
+`
+
+type UpdateFavouriteDetailsParams struct {
+	ID         int32       `json:"id"`
+	UserID     int32       `json:"user_id"`
+	Note       pgtype.Text `json:"note"`
+	Priority   pgtype.Text `json:"priority"`
+	Attendance pgtype.Text `json:"attendance"`
+}
+
+func (q *Queries) UpdateFavouriteDetails(ctx context.Context, arg UpdateFavouriteDetailsParams) (Favourite, error) {
+	row := q.db.QueryRow(ctx, updateFavouriteDetails,
+		arg.ID,
+		arg.UserID,
+		arg.Note,
+		arg.Priority,
+		arg.Attendance,
+	)
+	var i Favourite
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.EventGuid,
+		&i.EventID,
+		&i.ConferenceID,
+		&i.Note,
+		&i.Priority,
+		&i.Attendance,
+		&i.EventTitle,
+		&i.EventStart,
+		&i.EventPersons,
+		&i.OrphanedAt,
+	)
+	return i, err
+}
@@ -8,6 +8,15 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AgendaShare struct {
+	ID           int32              `json:"id"`
+	UserID       int32              `json:"user_id"`
+	ConferenceID int32              `json:"conference_id"`
+	Slug         string             `json:"slug"`
+	DisplayName  pgtype.Text        `json:"display_name"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
 type Calendar struct {
 	ID     int32  `json:"id"`
 	UserID int32  `json:"user_id"`
@@ -16,19 +25,137 @@ type Calendar struct {
 }
 
 type Conference struct {
-	ID    int32       `json:"id"`
-	Url   string      `json:"url"`
-	Title pgtype.Text `json:"title"`
-	Venue pgtype.Text `json:"venue"`
-	City  pgtype.Text `json:"city"`
+	ID                int32              `json:"id"`
+	Url               pgtype.Text        `json:"url"`
+	Title             pgtype.Text        `json:"title"`
+	Venue             pgtype.Text        `json:"venue"`
+	City              pgtype.Text        `json:"city"`
+	TimeZone          pgtype.Text        `json:"time_zone"`
+	StartsOn          pgtype.Date        `json:"starts_on"`
+	EndsOn            pgtype.Date        `json:"ends_on"`
+	Visibility        string             `json:"visibility"`
+	ArchivedAt        pgtype.Timestamptz `json:"archived_at"`
+	RefreshMinutes    int32              `json:"refresh_minutes"`
+	TitleOverride     pgtype.Text        `json:"title_override"`
+	DeletedAt         pgtype.Timestamptz `json:"deleted_at"`
+	Access            string             `json:"access"`
+	AccessClaim       pgtype.Text        `json:"access_claim"`
+	AccessClaimValues []string           `json:"access_claim_values"`
 }
 
-type Favourite struct {
+type ConferenceAccess struct {
 	ID           int32       `json:"id"`
-	UserID       int32       `json:"user_id"`
-	EventGuid    pgtype.UUID `json:"event_guid"`
-	EventID      pgtype.Int4 `json:"event_id"`
 	ConferenceID int32       `json:"conference_id"`
+	UserID       pgtype.Int4 `json:"user_id"`
+	GroupID      pgtype.Int4 `json:"group_id"`
+}
+
+type ConferenceSnapshot struct {
+	ConferenceID int32              `json:"conference_id"`
+	Schedule     []byte             `json:"schedule"`
+	FetchedAt    pgtype.Timestamptz `json:"fetched_at"`
+}
+
+type ConferenceSource struct {
+	ID           int32              `json:"id"`
+	ConferenceID int32              `json:"conference_id"`
+	Url          string             `json:"url"`
+	Label        string             `json:"label"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type CustomEvent struct {
+	ID           int32              `json:"id"`
+	ConferenceID int32              `json:"conference_id"`
+	Guid         pgtype.UUID        `json:"guid"`
+	OwnerID      pgtype.Int4        `json:"owner_id"`
+	Shared       bool               `json:"shared"`
+	Title        string             `json:"title"`
+	Abstract     string             `json:"abstract"`
+	Room         string             `json:"room"`
+	Url          string             `json:"url"`
+	StartTime    pgtype.Timestamptz `json:"start_time"`
+	EndTime      pgtype.Timestamptz `json:"end_time"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+type EmailDigest struct {
+	UserID       int32              `json:"user_id"`
+	ConferenceID int32              `json:"conference_id"`
+	Day          pgtype.Date        `json:"day"`
+	SentAt       pgtype.Timestamptz `json:"sent_at"`
+}
+
+type EmailVerification struct {
+	UserID    int32              `json:"user_id"`
+	Email     string             `json:"email"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type EventOverride struct {
+	ConferenceID int32              `json:"conference_id"`
+	EventGuid    pgtype.UUID        `json:"event_guid"`
+	StartTime    pgtype.Timestamptz `json:"start_time"`
+	EndTime      pgtype.Timestamptz `json:"end_time"`
+	Room         pgtype.Text        `json:"room"`
+	Title        pgtype.Text        `json:"title"`
+	Cancelled    bool               `json:"cancelled"`
+	Note         pgtype.Text        `json:"note"`
+	UpdatedBy    pgtype.Int4        `json:"updated_by"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Favourite struct {
+	ID           int32              `json:"id"`
+	UserID       int32              `json:"user_id"`
+	EventGuid    pgtype.UUID        `json:"event_guid"`
+	EventID      pgtype.Int4        `json:"event_id"`
+	ConferenceID int32              `json:"conference_id"`
+	Note         pgtype.Text        `json:"note"`
+	Priority     pgtype.Text        `json:"priority"`
+	Attendance   pgtype.Text        `json:"attendance"`
+	EventTitle   pgtype.Text        `json:"event_title"`
+	EventStart   pgtype.Timestamptz `json:"event_start"`
+	EventPersons []string           `json:"event_persons"`
+	OrphanedAt   pgtype.Timestamptz `json:"orphaned_at"`
+}
+
+type Notification struct {
+	ID           int32              `json:"id"`
+	UserID       int32              `json:"user_id"`
+	ConferenceID pgtype.Int4        `json:"conference_id"`
+	Kind         string             `json:"kind"`
+	Title        string             `json:"title"`
+	Body         string             `json:"body"`
+	EventGuid    pgtype.UUID        `json:"event_guid"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	ReadAt       pgtype.Timestamptz `json:"read_at"`
+}
+
+type PushReminder struct {
+	FavouriteID int32              `json:"favourite_id"`
+	EventStart  pgtype.Timestamptz `json:"event_start"`
+	SentAt      pgtype.Timestamptz `json:"sent_at"`
+}
+
+type PushSubscription struct {
+	ID        int32              `json:"id"`
+	UserID    int32              `json:"user_id"`
+	Endpoint  string             `json:"endpoint"`
+	P256dh    string             `json:"p256dh"`
+	Auth      string             `json:"auth"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type SpeakerFollow struct {
+	UserID       int32              `json:"user_id"`
+	ConferenceID int32              `json:"conference_id"`
+	PersonID     int32              `json:"person_id"`
+	PersonName   string             `json:"person_name"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 }
 
 type User struct {
@@ -37,3 +164,76 @@ type User struct {
 	Password pgtype.Text `json:"password"`
 	Admin    bool        `json:"admin"`
 }
+
+type UserGroup struct {
+	ID        int32              `json:"id"`
+	Name      string             `json:"name"`
+	OwnerID   int32              `json:"owner_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type UserGroupInvitation struct {
+	GroupID   int32              `json:"group_id"`
+	UserID    int32              `json:"user_id"`
+	InvitedBy int32              `json:"invited_by"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type UserGroupMember struct {
+	GroupID  int32              `json:"group_id"`
+	UserID   int32              `json:"user_id"`
+	JoinedAt pgtype.Timestamptz `json:"joined_at"`
+}
+
+type UserOidcClaim struct {
+	UserID    int32              `json:"user_id"`
+	Claims    []byte             `json:"claims"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type UserProfile struct {
+	UserID               int32              `json:"user_id"`
+	DisplayName          pgtype.Text        `json:"display_name"`
+	TimeZone             pgtype.Text        `json:"time_zone"`
+	Clock                string             `json:"clock"`
+	Language             pgtype.Text        `json:"language"`
+	DefaultConferenceID  pgtype.Int4        `json:"default_conference_id"`
+	FavouritesVisibility string             `json:"favourites_visibility"`
+	Email                pgtype.Text        `json:"email"`
+	EmailVerifiedAt      pgtype.Timestamptz `json:"email_verified_at"`
+	EmailDigest          bool               `json:"email_digest"`
+	EmailAlerts          bool               `json:"email_alerts"`
+}
+
+type VapidKey struct {
+	ID         int32              `json:"id"`
+	PrivateKey string             `json:"private_key"`
+	PublicKey  string             `json:"public_key"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type Webhook struct {
+	ID           int32              `json:"id"`
+	UserID       int32              `json:"user_id"`
+	ConferenceID int32              `json:"conference_id"`
+	Scope        string             `json:"scope"`
+	Url          string             `json:"url"`
+	Secret       string             `json:"secret"`
+	Events       []string           `json:"events"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int32              `json:"id"`
+	WebhookID      int32              `json:"webhook_id"`
+	Event          string             `json:"event"`
+	Payload        []byte             `json:"payload"`
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	NextAttemptAt  pgtype.Timestamptz `json:"next_attempt_at"`
+	ResponseStatus pgtype.Int4        `json:"response_status"`
+	ResponseBody   pgtype.Text        `json:"response_body"`
+	Error          pgtype.Text        `json:"error"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	CompletedAt    pgtype.Timestamptz `json:"completed_at"`
+}
@@ -81,6 +81,30 @@ func (q *Queries) GetUserByName(ctx context.Context, username string) (User, err
 	return i, err
 }
 
+const getUserProfile = `-- name: GetUserProfile :one
+SELECT user_id, display_name, time_zone, clock, language, default_conference_id, favourites_visibility, email, email_verified_at, email_digest, email_alerts FROM user_profiles
+WHERE user_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserProfile(ctx context.Context, userID int32) (UserProfile, error) {
+	row := q.db.QueryRow(ctx, getUserProfile, userID)
+	var i UserProfile
+	err := row.Scan(
+		&i.UserID,
+		&i.DisplayName,
+		&i.TimeZone,
+		&i.Clock,
+		&i.Language,
+		&i.DefaultConferenceID,
+		&i.FavouritesVisibility,
+		&i.Email,
+		&i.EmailVerifiedAt,
+		&i.EmailDigest,
+		&i.EmailAlerts,
+	)
+	return i, err
+}
+
 const listUsers = `-- name: ListUsers :many
 SELECT id, username, password, admin FROM users
 ORDER BY username
@@ -110,3 +134,62 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 	}
 	return items, nil
 }
+
+const upsertUserProfile = `-- name: UpsertUserProfile :one
+INSERT INTO user_profiles (
+  user_id, display_name, time_zone, clock, language, default_conference_id, favourites_visibility, email_digest, email_alerts
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+ON CONFLICT (user_id) DO UPDATE SET
+  display_name = EXCLUDED.display_name,
+  time_zone = EXCLUDED.time_zone,
+  clock = EXCLUDED.clock,
+  language = EXCLUDED.language,
+  default_conference_id = EXCLUDED.default_conference_id,
+  favourites_visibility = EXCLUDED.favourites_visibility,
+  email_digest = EXCLUDED.email_digest,
+  email_alerts = EXCLUDED.email_alerts
+RETURNING user_id, display_name, time_zone, clock, language, default_conference_id, favourites_visibility, email, email_verified_at, email_digest, email_alerts
+`
+
+type UpsertUserProfileParams struct {
+	UserID               int32       `json:"user_id"`
+	DisplayName          pgtype.Text `json:"display_name"`
+	TimeZone             pgtype.Text `json:"time_zone"`
+	Clock                string      `json:"clock"`
+	Language             pgtype.Text `json:"language"`
+	DefaultConferenceID  pgtype.Int4 `json:"default_conference_id"`
+	FavouritesVisibility string      `json:"favourites_visibility"`
+	EmailDigest          bool        `json:"email_digest"`
+	EmailAlerts          bool        `json:"email_alerts"`
+}
+
+func (q *Queries) UpsertUserProfile(ctx context.Context, arg UpsertUserProfileParams) (UserProfile, error) {
+	row := q.db.QueryRow(ctx, upsertUserProfile,
+		arg.UserID,
+		arg.DisplayName,
+		arg.TimeZone,
+		arg.Clock,
+		arg.Language,
+		arg.DefaultConferenceID,
+		arg.FavouritesVisibility,
+		arg.EmailDigest,
+		arg.EmailAlerts,
+	)
+	var i UserProfile
+	err := row.Scan(
+		&i.UserID,
+		&i.DisplayName,
+		&i.TimeZone,
+		&i.Clock,
+		&i.Language,
+		&i.DefaultConferenceID,
+		&i.FavouritesVisibility,
+		&i.Email,
+		&i.EmailVerifiedAt,
+		&i.EmailDigest,
+		&i.EmailAlerts,
+	)
+	return i, err
+}
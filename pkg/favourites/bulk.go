@@ -0,0 +1,159 @@
+package favourites
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// EventReference identifies an event in a conference's schedule, along with
+// any details to attach to the favourite when it is created.
+type EventReference struct {
+	EventGUID  pgtype.UUID
+	EventID    *int32
+	Note       string
+	Priority   string
+	Attendance string
+}
+
+func (s *service) CreateFavouritesForUser(id int32, conferenceID int32, events []EventReference, replace bool) ([]sqlc.Favourite, error) {
+	if err := s.checkAccess(id, conferenceID); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	queries := sqlc.New(s.pool).WithTx(tx)
+
+	var replaced []sqlc.Favourite
+	if replace {
+		replaced, err = queries.DeleteFavouritesForUserConference(ctx, sqlc.DeleteFavouritesForUserConferenceParams{
+			UserID:       id,
+			ConferenceID: conferenceID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not delete favourites: %w", err)
+		}
+	}
+
+	existing, err := queries.GetFavouritesForUserConference(ctx, sqlc.GetFavouritesForUserConferenceParams{
+		UserID:       id,
+		ConferenceID: conferenceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch favourites: %w", err)
+	}
+
+	created := make([]sqlc.Favourite, 0, len(events))
+	for _, event := range events {
+		params, err := s.createFavouriteParams(id, conferenceID, event.EventGUID, event.EventID)
+		if err != nil {
+			return nil, err
+		}
+		if isFavourited(existing, params.EventGuid, params.EventID) {
+			continue
+		}
+
+		params.Note = optionalText(event.Note)
+		params.Priority = optionalText(event.Priority)
+		params.Attendance = optionalText(event.Attendance)
+
+		favourite, err := queries.CreateFavourite(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("could not create favourite: %w", err)
+		}
+		existing = append(existing, favourite)
+		created = append(created, favourite)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	// listeners are only told about events which were not already
+	// favourites before they were replaced
+	change := FavouriteChange{
+		UserID:       id,
+		ConferenceID: conferenceID,
+	}
+	for _, favourite := range created {
+		if !isFavourited(replaced, favourite.EventGuid, favourite.EventID) {
+			change.Created = append(change.Created, favourite)
+		}
+	}
+	for _, favourite := range replaced {
+		if !isFavourited(created, favourite.EventGuid, favourite.EventID) {
+			change.Deleted = append(change.Deleted, favourite)
+		}
+	}
+	s.notifyListeners(change)
+
+	return created, nil
+}
+
+func (s *service) DeleteFavouritesForUser(id int32, conferenceID int32, events []EventReference) (int64, error) {
+	ctx := context.Background()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	queries := sqlc.New(s.pool).WithTx(tx)
+
+	var deleted []sqlc.Favourite
+	for _, event := range events {
+		var pgEventID pgtype.Int4
+		if event.EventID != nil {
+			pgEventID = pgtype.Int4{
+				Int32: *event.EventID,
+				Valid: true,
+			}
+		}
+
+		rows, err := queries.DeleteFavouriteByEventDetails(ctx, sqlc.DeleteFavouriteByEventDetailsParams{
+			EventGuid:    event.EventGUID,
+			EventID:      pgEventID,
+			UserID:       id,
+			ConferenceID: conferenceID,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("could not delete favourite: %w", err)
+		}
+		deleted = append(deleted, rows...)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	s.notifyListeners(FavouriteChange{
+		UserID:       id,
+		ConferenceID: conferenceID,
+		Deleted:      deleted,
+	})
+
+	return int64(len(deleted)), nil
+}
+
+func isFavourited(favourites []sqlc.Favourite, eventGUID pgtype.UUID, eventID pgtype.Int4) bool {
+	for _, favourite := range favourites {
+		if eventGUID.Valid {
+			if favourite.EventGuid.Valid && favourite.EventGuid.Bytes == eventGUID.Bytes {
+				return true
+			}
+		} else if eventID.Valid && favourite.EventID.Valid && favourite.EventID.Int32 == eventID.Int32 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package favourites
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+)
+
+// FavouriteEvent pairs a favourite with the schedule event it refers to.
+type FavouriteEvent struct {
+	Favourite sqlc.Favourite
+	Event     conference.Event
+}
+
+// GetFavouriteEventsForUserConference returns the user's favourites in a
+// conference alongside the events they refer to. Favourites which cannot be
+// found in the schedule are omitted.
+func (s *service) GetFavouriteEventsForUserConference(id int32, conferenceID int32) ([]FavouriteEvent, error) {
+	favourites, err := s.GetFavouritesForUserConference(id, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, _, err := s.conferenceService.GetScheduleForUser(conferenceID, id)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch schedule: %w", err)
+	}
+
+	return resolveFavourites(*favourites, schedule), nil
+}
+
+func (s *service) GetConflictsForUserConference(id int32, conferenceID int32, buffer time.Duration) ([][]FavouriteEvent, error) {
+	favouriteEvents, err := s.GetFavouriteEventsForUserConference(id, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// cancelled events won't happen, so can't clash with anything
+	events := make([]FavouriteEvent, 0, len(favouriteEvents))
+	for _, event := range favouriteEvents {
+		if !event.Event.Cancelled() {
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Event.Start.Before(events[j].Event.Start)
+	})
+
+	return conflictGroups(events, buffer), nil
+}
+
+// conflictGroups groups events which clash, directly or through another
+// event. Each group is a connected component of the overlaps between every
+// pair of events: a group can't be closed as soon as one event overlaps
+// none of it, as the walking buffer depends on the room and a later event
+// may still reach back. events must be sorted by start time.
+func conflictGroups(events []FavouriteEvent, buffer time.Duration) [][]FavouriteEvent {
+	parent := make([]int, len(events))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range events {
+		for j := 0; j < i; j++ {
+			if Overlaps(events[i].Event, events[j].Event, buffer) {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	groups := make(map[int][]FavouriteEvent)
+	var roots []int
+	for i, event := range events {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], event)
+	}
+
+	conflicts := make([][]FavouriteEvent, 0)
+	for _, root := range roots {
+		if len(groups[root]) > 1 {
+			conflicts = append(conflicts, groups[root])
+		}
+	}
+
+	return conflicts
+}
+
+// Overlaps reports whether two events overlap, or are too close together to
+// walk between if they are in different rooms. Cancelled events overlap
+// nothing.
+func Overlaps(a conference.Event, b conference.Event, buffer time.Duration) bool {
+	if a.Cancelled() || b.Cancelled() {
+		return false
+	}
+	if a.Room == b.Room {
+		buffer = 0
+	}
+	return a.Start.Before(b.End.Add(buffer)) && b.Start.Before(a.End.Add(buffer))
+}
+
+// resolveFavourites pairs favourites with their events in the schedule,
+// leaving out orphaned favourites and ones whose event has gone.
+func resolveFavourites(favourites []sqlc.Favourite, schedule *conference.Schedule) []FavouriteEvent {
+	eventsByGUID := make(map[string]conference.Event)
+	eventsByID := schedule.EventsByID()
+	for _, day := range schedule.Days {
+		for _, room := range day.Rooms {
+			for _, event := range room.Events {
+				eventsByGUID[event.GUID] = event
+			}
+		}
+	}
+
+	resolved := make([]FavouriteEvent, 0, len(favourites))
+	for _, favourite := range favourites {
+		if favourite.OrphanedAt.Valid {
+			continue
+		}
+
+		var event conference.Event
+		var ok bool
+		if favourite.EventGuid.Valid {
+			event, ok = eventsByGUID[favourite.EventGuid.String()]
+		} else if favourite.EventID.Valid {
+			// IDs are not stable across re-published schedules, so as when
+			// reconciling, only trust the ID if it looks like the same event
+			event, ok = eventsByID[favourite.EventID.Int32]
+			ok = ok && (!favourite.EventTitle.Valid || similarity(favourite, event) >= fuzzyMatchThreshold)
+		}
+		if !ok {
+			continue
+		}
+
+		resolved = append(resolved, FavouriteEvent{
+			Favourite: favourite,
+			Event:     event,
+		})
+	}
+	return resolved
+}
@@ -0,0 +1,243 @@
+package favourites
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// minimum score for a fuzzy match to be considered the same event
+const fuzzyMatchThreshold = 0.7
+
+// ReconcileFavourites re-binds every favourite in a conference to the
+// events in a freshly fetched schedule. Favourites are matched by GUID
+// first, then by ID if the favourite's event snapshot still agrees, and
+// finally by fuzzy matching on title, speakers and start time. Favourites
+// which cannot be matched are marked as orphaned.
+func (s *service) ReconcileFavourites(conferenceID int32, schedule *conference.Schedule) error {
+	queries := sqlc.New(s.pool)
+
+	favourites, err := queries.GetFavouritesForConference(context.Background(), conferenceID)
+	if err != nil {
+		return fmt.Errorf("could not fetch favourites: %w", err)
+	}
+
+	eventsByGUID := make(map[string]conference.Event)
+	eventsByID := schedule.EventsByID()
+	events := make([]conference.Event, 0)
+	for _, day := range schedule.Days {
+		for _, room := range day.Rooms {
+			for _, event := range room.Events {
+				eventsByGUID[event.GUID] = event
+				events = append(events, event)
+			}
+		}
+	}
+
+	var rebound, orphaned int
+	for _, favourite := range favourites {
+		if isPersonalEvent(favourite) {
+			// personal custom events aren't part of the shared schedule,
+			// so leave them be as long as they still exist
+			if _, err := s.conferenceService.GetEventByGUID(conferenceID, favourite.EventGuid.String(), favourite.UserID); err == nil {
+				continue
+			}
+		}
+
+		event, ok := matchFavourite(favourite, eventsByGUID, eventsByID, events)
+		if !ok {
+			if !favourite.OrphanedAt.Valid {
+				if err := queries.MarkFavouriteOrphaned(context.Background(), favourite.ID); err != nil {
+					return fmt.Errorf("could not mark favourite as orphaned: %w", err)
+				}
+				orphaned++
+			}
+			continue
+		}
+
+		if isBoundTo(favourite, event) {
+			continue
+		}
+
+		params := rebindParams(favourite.ID, event)
+		if err := queries.RebindFavourite(context.Background(), params); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				// the user has since favourited the event again, so this
+				// favourite is now a duplicate
+				if err := queries.DeleteFavourite(context.Background(), favourite.ID); err != nil {
+					return fmt.Errorf("could not delete duplicate favourite: %w", err)
+				}
+				continue
+			}
+			return fmt.Errorf("could not rebind favourite: %w", err)
+		}
+		rebound++
+	}
+
+	if rebound > 0 || orphaned > 0 {
+		slog.Info("reconciled favourites", "conference", conferenceID, "rebound", rebound, "orphaned", orphaned)
+	}
+
+	return nil
+}
+
+func matchFavourite(favourite sqlc.Favourite, eventsByGUID map[string]conference.Event, eventsByID map[int32]conference.Event, events []conference.Event) (conference.Event, bool) {
+	if favourite.EventGuid.Valid {
+		if event, ok := eventsByGUID[favourite.EventGuid.String()]; ok {
+			return event, true
+		}
+	}
+
+	if favourite.EventID.Valid {
+		if event, ok := eventsByID[favourite.EventID.Int32]; ok {
+			// IDs are not stable across re-published schedules, so only
+			// trust the ID if it still looks like the same event
+			if !favourite.EventTitle.Valid || similarity(favourite, event) >= fuzzyMatchThreshold {
+				return event, true
+			}
+		}
+	}
+
+	if !favourite.EventTitle.Valid {
+		return conference.Event{}, false
+	}
+
+	var best conference.Event
+	var bestScore float64
+	var ambiguous bool
+	for _, event := range events {
+		score := similarity(favourite, event)
+		if score > bestScore {
+			best = event
+			bestScore = score
+			ambiguous = false
+		} else if score == bestScore {
+			ambiguous = true
+		}
+	}
+
+	if bestScore < fuzzyMatchThreshold || ambiguous {
+		return conference.Event{}, false
+	}
+	return best, true
+}
+
+// similarity scores how likely it is that an event is the one a favourite
+// was originally made against, between 0 and 1.
+func similarity(favourite sqlc.Favourite, event conference.Event) float64 {
+	titleScore := jaccard(tokenise(favourite.EventTitle.String), tokenise(event.Title))
+
+	var speakers []string
+	for _, person := range event.Persons {
+		speakers = append(speakers, person.Name)
+	}
+	speakerScore := jaccard(normaliseAll(favourite.EventPersons), normaliseAll(speakers))
+	if len(favourite.EventPersons) == 0 && len(speakers) == 0 {
+		speakerScore = 1
+	}
+
+	timeScore := 0.0
+	if favourite.EventStart.Valid {
+		difference := math.Abs(event.Start.Sub(favourite.EventStart.Time).Hours())
+		timeScore = math.Max(0, 1-difference/24)
+	}
+
+	return 0.6*titleScore + 0.25*speakerScore + 0.15*timeScore
+}
+
+// isPersonalEvent reports whether a favourite is of a custom event, which
+// are given negative IDs.
+func isPersonalEvent(favourite sqlc.Favourite) bool {
+	return favourite.EventGuid.Valid && favourite.EventID.Valid && favourite.EventID.Int32 < 0
+}
+
+func isBoundTo(favourite sqlc.Favourite, event conference.Event) bool {
+	return favourite.EventGuid.Valid && favourite.EventGuid.String() == event.GUID &&
+		favourite.EventID.Valid && favourite.EventID.Int32 == event.ID &&
+		favourite.EventTitle.String == event.Title &&
+		favourite.EventStart.Time.Equal(event.Start) &&
+		!favourite.OrphanedAt.Valid
+}
+
+func rebindParams(id int32, event conference.Event) sqlc.RebindFavouriteParams {
+	snapshot := snapshotEvent(event)
+	return sqlc.RebindFavouriteParams{
+		ID:           id,
+		EventGuid:    snapshot.guid,
+		EventID:      pgtype.Int4{Int32: event.ID, Valid: true},
+		EventTitle:   snapshot.title,
+		EventStart:   snapshot.start,
+		EventPersons: snapshot.persons,
+	}
+}
+
+type eventSnapshot struct {
+	guid    pgtype.UUID
+	title   pgtype.Text
+	start   pgtype.Timestamptz
+	persons []string
+}
+
+func snapshotEvent(event conference.Event) eventSnapshot {
+	var snapshot eventSnapshot
+	snapshot.guid.Scan(event.GUID)
+	snapshot.title = pgtype.Text{String: event.Title, Valid: true}
+	snapshot.start = pgtype.Timestamptz{Time: event.Start, Valid: true}
+	snapshot.persons = make([]string, 0, len(event.Persons))
+	for _, person := range event.Persons {
+		snapshot.persons = append(snapshot.persons, person.Name)
+	}
+	return snapshot
+}
+
+func tokenise(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func normaliseAll(values []string) []string {
+	normalised := make([]string, 0, len(values))
+	for _, value := range values {
+		normalised = append(normalised, strings.Join(tokenise(value), " "))
+	}
+	return normalised
+}
+
+func jaccard(a []string, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	set := make(map[string]bool)
+	for _, v := range a {
+		set[v] = true
+	}
+
+	union := len(set)
+	intersection := 0
+	seen := make(map[string]bool)
+	for _, v := range b {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		if set[v] {
+			intersection++
+		} else {
+			union++
+		}
+	}
+
+	return float64(intersection) / float64(union)
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
+	"github.com/LMBishop/confplanner/pkg/conference"
 	"github.com/LMBishop/confplanner/pkg/database/sqlc"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -16,6 +19,36 @@ type Service interface {
 	GetFavouritesForUserConference(id int32, conference int32) (*[]sqlc.Favourite, error)
 	CreateFavouriteForUser(id int32, eventGUID pgtype.UUID, eventID *int32, conferenceID int32) (*sqlc.Favourite, error)
 	DeleteFavouriteForUserByEventDetails(id int32, eventGUID pgtype.UUID, eventID *int32, conferenceID int32) error
+	UpdateFavouriteForUser(id int32, favouriteID int32, update FavouriteUpdate) (*sqlc.Favourite, error)
+	GetFavouriteEventsForUserConference(id int32, conferenceID int32) ([]FavouriteEvent, error)
+	GetConflictsForUserConference(id int32, conferenceID int32, buffer time.Duration) ([][]FavouriteEvent, error)
+	ReconcileFavourites(conferenceID int32, schedule *conference.Schedule) error
+	CreateFavouritesForUser(id int32, conferenceID int32, events []EventReference, replace bool) ([]sqlc.Favourite, error)
+	DeleteFavouritesForUser(id int32, conferenceID int32, events []EventReference) (int64, error)
+	AddFavouriteListener(listener FavouriteListener)
+}
+
+// FavouriteChange describes favourites a user created, updated or deleted
+// in a conference at once.
+type FavouriteChange struct {
+	UserID       int32
+	ConferenceID int32
+	Created      []sqlc.Favourite
+	Updated      []sqlc.Favourite
+	Deleted      []sqlc.Favourite
+}
+
+// FavouriteListener is called whenever a user's favourites in a conference
+// have been created, updated or deleted.
+type FavouriteListener func(change FavouriteChange)
+
+// FavouriteUpdate describes a partial update to the details a user has
+// attached to a favourite. Nil fields are left unchanged, and empty values
+// clear the detail.
+type FavouriteUpdate struct {
+	Note       *string
+	Priority   *string
+	Attendance *string
 }
 
 var (
@@ -24,18 +57,46 @@ var (
 )
 
 type service struct {
-	pool *pgxpool.Pool
+	pool              *pgxpool.Pool
+	conferenceService conference.Service
+	listeners         []FavouriteListener
+	lock              sync.RWMutex
 }
 
-func NewService(pool *pgxpool.Pool) Service {
+func NewService(pool *pgxpool.Pool, conferenceService conference.Service) Service {
 	return &service{
-		pool: pool,
+		pool:              pool,
+		conferenceService: conferenceService,
 	}
 }
 
 func (s *service) CreateFavouriteForUser(userID int32, eventGUID pgtype.UUID, eventID *int32, conferenceID int32) (*sqlc.Favourite, error) {
+	if err := s.checkAccess(userID, conferenceID); err != nil {
+		return nil, err
+	}
+
 	queries := sqlc.New(s.pool)
 
+	params, err := s.createFavouriteParams(userID, conferenceID, eventGUID, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	favourite, err := queries.CreateFavourite(context.Background(), params)
+	if err != nil {
+		return nil, fmt.Errorf("could not create favourite: %w", err)
+	}
+
+	s.notifyListeners(FavouriteChange{
+		UserID:       userID,
+		ConferenceID: conferenceID,
+		Created:      []sqlc.Favourite{favourite},
+	})
+
+	return &favourite, nil
+}
+
+func (s *service) createFavouriteParams(userID int32, conferenceID int32, eventGUID pgtype.UUID, eventID *int32) (sqlc.CreateFavouriteParams, error) {
 	var pgEventID pgtype.Int4
 	if eventID != nil {
 		pgEventID = pgtype.Int4{
@@ -44,17 +105,30 @@ func (s *service) CreateFavouriteForUser(userID int32, eventGUID pgtype.UUID, ev
 		}
 	}
 
-	favourite, err := queries.CreateFavourite(context.Background(), sqlc.CreateFavouriteParams{
+	// record what the event looked like so the favourite can be matched
+	// up again if the schedule is re-published with different IDs
+	var snapshot eventSnapshot
+	event, err := s.lookupEvent(userID, conferenceID, eventGUID, eventID)
+	if err == nil {
+		snapshot = snapshotEvent(*event)
+		eventGUID = snapshot.guid
+		pgEventID = pgtype.Int4{
+			Int32: event.ID,
+			Valid: true,
+		}
+	} else if !errors.Is(err, conference.ErrEventNotFound) {
+		return sqlc.CreateFavouriteParams{}, err
+	}
+
+	return sqlc.CreateFavouriteParams{
 		UserID:       userID,
 		EventGuid:    eventGUID,
 		EventID:      pgEventID,
 		ConferenceID: conferenceID,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("could not create favourite: %w", err)
-	}
-
-	return &favourite, nil
+		EventTitle:   snapshot.title,
+		EventStart:   snapshot.start,
+		EventPersons: snapshot.persons,
+	}, nil
 }
 
 func (s *service) GetAllFavouritesForUser(userID int32) (*[]sqlc.Favourite, error) {
@@ -73,6 +147,10 @@ func (s *service) GetAllFavouritesForUser(userID int32) (*[]sqlc.Favourite, erro
 }
 
 func (s *service) GetFavouritesForUserConference(userID int32, conferenceID int32) (*[]sqlc.Favourite, error) {
+	if err := s.checkAccess(userID, conferenceID); err != nil {
+		return nil, err
+	}
+
 	queries := sqlc.New(s.pool)
 
 	favourites, err := queries.GetFavouritesForUserConference(context.Background(), sqlc.GetFavouritesForUserConferenceParams{
@@ -100,7 +178,7 @@ func (s *service) DeleteFavouriteForUserByEventDetails(id int32, eventGUID pgtyp
 			Valid: true,
 		}
 	}
-	rowsAffected, err := queries.DeleteFavouriteByEventDetails(context.Background(), sqlc.DeleteFavouriteByEventDetailsParams{
+	deleted, err := queries.DeleteFavouriteByEventDetails(context.Background(), sqlc.DeleteFavouriteByEventDetailsParams{
 		EventGuid:    eventGUID,
 		EventID:      pgEventID,
 		UserID:       id,
@@ -109,9 +187,112 @@ func (s *service) DeleteFavouriteForUserByEventDetails(id int32, eventGUID pgtyp
 	if err != nil {
 		return fmt.Errorf("could not delete favourite: %w", err)
 	}
-	if rowsAffected == 0 {
+	if len(deleted) == 0 {
 		return ErrNotFound
 	}
 
+	s.notifyListeners(FavouriteChange{
+		UserID:       id,
+		ConferenceID: conferenceID,
+		Deleted:      deleted,
+	})
+
 	return nil
 }
+
+func (s *service) UpdateFavouriteForUser(id int32, favouriteID int32, update FavouriteUpdate) (*sqlc.Favourite, error) {
+	queries := sqlc.New(s.pool)
+
+	favourite, err := queries.GetFavouriteForUser(context.Background(), sqlc.GetFavouriteForUserParams{
+		ID:     favouriteID,
+		UserID: id,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("could not fetch favourite: %w", err)
+	}
+
+	if update.Note != nil {
+		favourite.Note = optionalText(*update.Note)
+	}
+	if update.Priority != nil {
+		favourite.Priority = optionalText(*update.Priority)
+	}
+	if update.Attendance != nil {
+		favourite.Attendance = optionalText(*update.Attendance)
+	}
+
+	updatedFavourite, err := queries.UpdateFavouriteDetails(context.Background(), sqlc.UpdateFavouriteDetailsParams{
+		ID:         favouriteID,
+		UserID:     id,
+		Note:       favourite.Note,
+		Priority:   favourite.Priority,
+		Attendance: favourite.Attendance,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not update favourite: %w", err)
+	}
+
+	s.notifyListeners(FavouriteChange{
+		UserID:       id,
+		ConferenceID: updatedFavourite.ConferenceID,
+		Updated:      []sqlc.Favourite{updatedFavourite},
+	})
+
+	return &updatedFavourite, nil
+}
+
+func (s *service) AddFavouriteListener(listener FavouriteListener) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.listeners = append(s.listeners, listener)
+}
+
+func (s *service) notifyListeners(change FavouriteChange) {
+	if len(change.Created) == 0 && len(change.Updated) == 0 && len(change.Deleted) == 0 {
+		return
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, listener := range s.listeners {
+		go listener(change)
+	}
+}
+
+func optionalText(s string) pgtype.Text {
+	return pgtype.Text{
+		String: s,
+		Valid:  s != "",
+	}
+}
+
+// checkAccess returns conference.ErrConferenceNotFound if the user can't
+// access the conference, so as not to reveal it.
+func (s *service) checkAccess(userID int32, conferenceID int32) error {
+	ok, err := s.conferenceService.CanAccess(conferenceID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return conference.ErrConferenceNotFound
+	}
+	return nil
+}
+
+func (s *service) lookupEvent(userID int32, conferenceID int32, eventGUID pgtype.UUID, eventID *int32) (*conference.Event, error) {
+	if eventGUID.Valid {
+		event, err := s.conferenceService.GetEventByGUID(conferenceID, eventGUID.String(), userID)
+		if !errors.Is(err, conference.ErrEventNotFound) {
+			return event, err
+		}
+	}
+	if eventID != nil {
+		return s.conferenceService.GetEventByID(conferenceID, *eventID)
+	}
+	return nil, conference.ErrEventNotFound
+}
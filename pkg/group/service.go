@@ -0,0 +1,335 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Service interface {
+	CreateGroup(id int32, name string) (*sqlc.UserGroup, error)
+	GetGroupsForUser(id int32) ([]sqlc.UserGroup, error)
+	GetMembers(id int32, groupID int32) ([]sqlc.GetUserGroupMembersRow, error)
+	InviteUser(id int32, groupID int32, username string) error
+	GetInvitationsForUser(id int32) ([]sqlc.GetUserGroupInvitationsForUserRow, error)
+	JoinGroup(id int32, groupID int32) error
+	DeclineInvitation(id int32, groupID int32) error
+	LeaveGroup(id int32, groupID int32) error
+	GetGroupAgenda(id int32, groupID int32, conferenceID int32) ([]AgendaEvent, error)
+}
+
+// AgendaEvent is an event in a conference schedule along with the members
+// of a group who have favourited it and are happy for the group to see.
+type AgendaEvent struct {
+	EventGUID pgtype.UUID
+	EventID   pgtype.Int4
+	Members   []sqlc.GetUserGroupFavouritesRow
+}
+
+var (
+	ErrGroupNotFound      = errors.New("group not found")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrAlreadyMember      = errors.New("user is already a member")
+	ErrInvitationNotFound = errors.New("invitation not found")
+)
+
+type service struct {
+	pool              *pgxpool.Pool
+	conferenceService conference.Service
+}
+
+func NewService(pool *pgxpool.Pool, conferenceService conference.Service) Service {
+	return &service{
+		pool:              pool,
+		conferenceService: conferenceService,
+	}
+}
+
+func (s *service) CreateGroup(id int32, name string) (*sqlc.UserGroup, error) {
+	ctx := context.Background()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	queries := sqlc.New(s.pool).WithTx(tx)
+
+	group, err := queries.CreateUserGroup(ctx, sqlc.CreateUserGroupParams{
+		Name:    name,
+		OwnerID: id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create group: %w", err)
+	}
+
+	if err := queries.AddUserGroupMember(ctx, sqlc.AddUserGroupMemberParams{
+		GroupID: group.ID,
+		UserID:  id,
+	}); err != nil {
+		return nil, fmt.Errorf("could not add group member: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return &group, nil
+}
+
+func (s *service) GetGroupsForUser(id int32) ([]sqlc.UserGroup, error) {
+	queries := sqlc.New(s.pool)
+
+	groups, err := queries.GetUserGroupsForUser(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+func (s *service) GetMembers(id int32, groupID int32) ([]sqlc.GetUserGroupMembersRow, error) {
+	if err := s.checkMember(id, groupID); err != nil {
+		return nil, err
+	}
+
+	queries := sqlc.New(s.pool)
+
+	members, err := queries.GetUserGroupMembers(context.Background(), groupID)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch group members: %w", err)
+	}
+
+	return members, nil
+}
+
+func (s *service) InviteUser(id int32, groupID int32, username string) error {
+	if err := s.checkMember(id, groupID); err != nil {
+		return err
+	}
+
+	queries := sqlc.New(s.pool)
+
+	invitee, err := queries.GetUserByName(context.Background(), username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("could not fetch user: %w", err)
+	}
+
+	if err := s.checkMember(invitee.ID, groupID); err == nil {
+		return ErrAlreadyMember
+	} else if !errors.Is(err, ErrGroupNotFound) {
+		return err
+	}
+
+	if err := queries.CreateUserGroupInvitation(context.Background(), sqlc.CreateUserGroupInvitationParams{
+		GroupID:   groupID,
+		UserID:    invitee.ID,
+		InvitedBy: id,
+	}); err != nil {
+		return fmt.Errorf("could not create invitation: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) GetInvitationsForUser(id int32) ([]sqlc.GetUserGroupInvitationsForUserRow, error) {
+	queries := sqlc.New(s.pool)
+
+	invitations, err := queries.GetUserGroupInvitationsForUser(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
+func (s *service) JoinGroup(id int32, groupID int32) error {
+	ctx := context.Background()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	queries := sqlc.New(s.pool).WithTx(tx)
+
+	rowsAffected, err := queries.DeleteUserGroupInvitation(ctx, sqlc.DeleteUserGroupInvitationParams{
+		GroupID: groupID,
+		UserID:  id,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete invitation: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrInvitationNotFound
+	}
+
+	if err := queries.AddUserGroupMember(ctx, sqlc.AddUserGroupMemberParams{
+		GroupID: groupID,
+		UserID:  id,
+	}); err != nil {
+		return fmt.Errorf("could not add group member: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) DeclineInvitation(id int32, groupID int32) error {
+	queries := sqlc.New(s.pool)
+
+	rowsAffected, err := queries.DeleteUserGroupInvitation(context.Background(), sqlc.DeleteUserGroupInvitationParams{
+		GroupID: groupID,
+		UserID:  id,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete invitation: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+func (s *service) LeaveGroup(id int32, groupID int32) error {
+	ctx := context.Background()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	queries := sqlc.New(s.pool).WithTx(tx)
+
+	group, err := queries.GetUserGroup(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrGroupNotFound
+		}
+		return fmt.Errorf("could not fetch group: %w", err)
+	}
+
+	rowsAffected, err := queries.DeleteUserGroupMember(ctx, sqlc.DeleteUserGroupMemberParams{
+		GroupID: groupID,
+		UserID:  id,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete group member: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrGroupNotFound
+	}
+
+	// hand the group over to whoever has been in it the longest, or get
+	// rid of it entirely if nobody is left
+	if group.OwnerID == id {
+		member, err := queries.GetOldestUserGroupMember(ctx, groupID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			if err := queries.DeleteUserGroup(ctx, groupID); err != nil {
+				return fmt.Errorf("could not delete group: %w", err)
+			}
+		} else if err != nil {
+			return fmt.Errorf("could not fetch group member: %w", err)
+		} else if err := queries.UpdateUserGroupOwner(ctx, sqlc.UpdateUserGroupOwnerParams{
+			ID:      groupID,
+			OwnerID: member.UserID,
+		}); err != nil {
+			return fmt.Errorf("could not update group owner: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) GetGroupAgenda(id int32, groupID int32, conferenceID int32) ([]AgendaEvent, error) {
+	if err := s.checkMember(id, groupID); err != nil {
+		return nil, err
+	}
+
+	// conferences the user can't access are reported as not found
+	ok, err := s.conferenceService.CanAccess(conferenceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, conference.ErrConferenceNotFound
+	}
+
+	queries := sqlc.New(s.pool)
+
+	rows, err := queries.GetUserGroupFavourites(context.Background(), sqlc.GetUserGroupFavouritesParams{
+		GroupID:      groupID,
+		ConferenceID: conferenceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch group favourites: %w", err)
+	}
+
+	events := make([]AgendaEvent, 0)
+	indexes := make(map[string]int)
+	for _, row := range rows {
+		var key string
+		if row.EventGuid.Valid {
+			key = row.EventGuid.String()
+		} else {
+			key = strconv.Itoa(int(row.EventID.Int32))
+		}
+
+		i, ok := indexes[key]
+		if !ok {
+			i = len(events)
+			indexes[key] = i
+			events = append(events, AgendaEvent{
+				EventGUID: row.EventGuid,
+				EventID:   row.EventID,
+			})
+		}
+		events[i].Members = append(events[i].Members, row)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return len(events[i].Members) > len(events[j].Members)
+	})
+
+	return events, nil
+}
+
+func (s *service) checkMember(id int32, groupID int32) error {
+	queries := sqlc.New(s.pool)
+
+	_, err := queries.GetUserGroupMember(context.Background(), sqlc.GetUserGroupMemberParams{
+		GroupID: groupID,
+		UserID:  id,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// don't reveal the existence of groups the user isn't in
+			return ErrGroupNotFound
+		}
+		return fmt.Errorf("could not fetch group member: %w", err)
+	}
+
+	return nil
+}
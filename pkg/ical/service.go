@@ -1,7 +1,10 @@
 package ical
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"io"
 	"strconv"
 	"strings"
 	"time"
@@ -9,11 +12,14 @@ import (
 	"github.com/LMBishop/confplanner/pkg/conference"
 	"github.com/LMBishop/confplanner/pkg/database/sqlc"
 	"github.com/LMBishop/confplanner/pkg/favourites"
+	"github.com/LMBishop/confplanner/pkg/user"
 	"github.com/microcosm-cc/bluemonday"
 )
 
 type Service interface {
-	GenerateIcalForCalendar(calendar sqlc.Calendar) (string, error)
+	// GenerateIcalForCalendar returns the calendar along with an ETag which
+	// only changes when the events in the calendar do.
+	GenerateIcalForCalendar(calendar sqlc.Calendar) (string, string, error)
 }
 
 var (
@@ -21,39 +27,108 @@ var (
 	ErrNotFound     = errors.New("not found")
 )
 
+type favouriteEvent struct {
+	favourite sqlc.Favourite
+	event     conference.Event
+}
+
 type service struct {
 	favouritesService favourites.Service
 	conferenceService conference.Service
+	userService       user.Service
+	conflictBuffer    time.Duration
 }
 
 func NewService(
 	favouritesService favourites.Service,
 	conferenceService conference.Service,
+	userService user.Service,
+	conflictBuffer time.Duration,
 ) Service {
 	return &service{
 		favouritesService: favouritesService,
 		conferenceService: conferenceService,
+		userService:       userService,
+		conflictBuffer:    conflictBuffer,
 	}
 }
 
-func (s *service) GenerateIcalForCalendar(calendar sqlc.Calendar) (string, error) {
-	favourites, err := s.favouritesService.GetAllFavouritesForUser(calendar.UserID)
+func (s *service) GenerateIcalForCalendar(calendar sqlc.Calendar) (string, string, error) {
+	userFavourites, err := s.favouritesService.GetAllFavouritesForUser(calendar.UserID)
 	if err != nil {
-		return "", err
+		return "", "", err
+	}
+
+	// favourites in conferences the user can no longer access are left out
+	accessible := make(map[int32]bool)
+	for _, favourite := range *userFavourites {
+		if _, ok := accessible[favourite.ConferenceID]; ok {
+			continue
+		}
+		ok, err := s.conferenceService.CanAccess(favourite.ConferenceID, calendar.UserID)
+		if err != nil && !errors.Is(err, conference.ErrConferenceNotFound) {
+			return "", "", err
+		}
+		accessible[favourite.ConferenceID] = ok
+	}
+
+	events := make([]favouriteEvent, 0)
+	for _, favourite := range *userFavourites {
+		var event *conference.Event
+		if favourite.OrphanedAt.Valid || !accessible[favourite.ConferenceID] {
+			continue
+		} else if favourite.EventGuid.Valid {
+			event, err = s.conferenceService.GetEventByGUID(favourite.ConferenceID, favourite.EventGuid.String(), calendar.UserID)
+		} else {
+			event, err = s.conferenceService.GetEventByID(favourite.ConferenceID, favourite.EventID.Int32)
+		}
+		if err != nil {
+			continue
+		}
+		events = append(events, favouriteEvent{
+			favourite: favourite,
+			event:     *event,
+		})
 	}
 
-	events := make([]conference.Event, 0)
-	for _, favourite := range *favourites {
-		event, err := s.conferenceService.GetEventByID(favourite.ConferenceID, favourite.EventID.Int32)
+	conflicts := make(map[int32][]string)
+	checkedConferences := make(map[int32]bool)
+	for _, favourite := range *userFavourites {
+		if checkedConferences[favourite.ConferenceID] || !accessible[favourite.ConferenceID] {
+			continue
+		}
+		checkedConferences[favourite.ConferenceID] = true
+
+		groups, err := s.favouritesService.GetConflictsForUserConference(calendar.UserID, favourite.ConferenceID, s.conflictBuffer)
 		if err != nil {
 			continue
 		}
-		events = append(events, *event)
+		for _, group := range groups {
+			for _, a := range group {
+				for _, b := range group {
+					if a.Favourite.ID != b.Favourite.ID && favourites.Overlaps(a.Event, b.Event, s.conflictBuffer) {
+						conflicts[a.Favourite.ID] = append(conflicts[a.Favourite.ID], b.Event.Title)
+					}
+				}
+			}
+		}
+	}
+
+	profile, err := s.userService.GetProfile(calendar.UserID)
+	if err != nil {
+		return "", "", err
 	}
+	location := user.Location(profile)
+	lastSynchronised := time.Now().In(location).Format("Mon, 02 Jan 2006 " + user.TimeLayout(profile) + " MST")
 
 	now := time.Now()
 	counter := 0
 
+	// the timestamps and last synchronised time differ on every request, so
+	// are left out of the ETag
+	hash := sha256.New()
+	io.WriteString(hash, location.String())
+
 	// https://www.rfc-editor.org/rfc/rfc5545.html
 
 	ret := "BEGIN:VCALENDAR\r\n"
@@ -61,18 +136,27 @@ func (s *service) GenerateIcalForCalendar(calendar sqlc.Calendar) (string, error
 	ret += "VERSION:2.0\r\n"
 	ret += "METHOD:PUBLISH\r\n"
 	ret += "X-WR-CALNAME:confplanner calendar\r\n"
-	for _, event := range events {
+	ret += "X-WR-TIMEZONE:" + location.String() + "\r\n"
+	for _, favouriteEvent := range events {
+		event := favouriteEvent.event
 		utcStart := event.Start.UTC()
 		utcEnd := event.End.UTC()
 
+		vevent := "SUMMARY:" + event.Title + "\r\n"
+		vevent += "DTSTART:" + utcStart.Format("20060102T150405Z") + "\r\n"
+		vevent += "DTEND:" + utcEnd.Format("20060102T150405Z") + "\r\n"
+		vevent += "LOCATION:" + event.Room + "\r\n"
+		if event.Cancelled() {
+			vevent += "STATUS:CANCELLED\r\n"
+		}
+		description := bluemonday.StrictPolicy().Sanitize(strings.Replace(event.Abstract, "\n", "\\n\\n", -1)) + describeFavourite(favouriteEvent.favourite) + describeConflicts(conflicts[favouriteEvent.favourite.ID])
+		io.WriteString(hash, vevent+description)
+
 		ret += "BEGIN:VEVENT\r\n"
-		ret += "SUMMARY:" + event.Title + "\r\n"
 		ret += "UID:" + now.Format("20060102T150405Z") + "-" + strconv.Itoa(counter) + "\r\n"
 		ret += "DTSTAMP:" + now.Format("20060102T150405Z") + "\r\n"
-		ret += "DTSTART:" + utcStart.Format("20060102T150405Z") + "\r\n"
-		ret += "DTEND:" + utcEnd.Format("20060102T150405Z") + "\r\n"
-		ret += "LOCATION:" + event.Room + "\r\n"
-		ret += "DESCRIPTION;ENCODING=QUOTED-PRINTABLE:" + bluemonday.StrictPolicy().Sanitize(strings.Replace(event.Abstract, "\n", "\\n\\n", -1)) + "\\n\\nconfplanner: last synchronised: " + now.Format(time.RFC1123) + "\r\n"
+		ret += vevent
+		ret += "DESCRIPTION;ENCODING=QUOTED-PRINTABLE:" + description + "\\n\\nconfplanner: last synchronised: " + lastSynchronised + "\r\n"
 
 		ret += "BEGIN:VALARM\r\n"
 		ret += "TRIGGER:-PT10M\r\n"
@@ -85,5 +169,26 @@ func (s *service) GenerateIcalForCalendar(calendar sqlc.Calendar) (string, error
 	}
 	ret += "END:VCALENDAR\r\n"
 
-	return ret, nil
+	return ret, `W/"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`, nil
+}
+
+func describeFavourite(favourite sqlc.Favourite) string {
+	ret := ""
+	if favourite.Priority.Valid {
+		ret += "\\n\\nconfplanner: priority: " + favourite.Priority.String
+	}
+	if favourite.Attendance.Valid {
+		ret += "\\n\\nconfplanner: " + favourite.Attendance.String
+	}
+	if favourite.Note.Valid {
+		ret += "\\n\\nconfplanner: note: " + bluemonday.StrictPolicy().Sanitize(strings.Replace(favourite.Note.String, "\n", "\\n", -1))
+	}
+	return ret
+}
+
+func describeConflicts(titles []string) string {
+	if len(titles) == 0 {
+		return ""
+	}
+	return "\\n\\nconfplanner: conflicts with: " + bluemonday.StrictPolicy().Sanitize(strings.Join(titles, ", "))
 }
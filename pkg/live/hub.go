@@ -0,0 +1,276 @@
+package live
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/conference"
+)
+
+type Hub interface {
+	Subscribe(conferenceID int32, userID int32, lastEventID string) (*Subscription, []Message)
+	Unsubscribe(subscription *Subscription)
+	Publish(conferenceID int32, userID int32, event string, data any)
+	PublishSchedule(conferenceID int32, schedule *conference.Schedule)
+}
+
+// Message is a single event sent to clients watching a conference. Messages
+// with a UserID are only sent to that user.
+type Message struct {
+	ID           uint64
+	Event        string
+	Data         []byte
+	ConferenceID int32
+	UserID       int32
+}
+
+const (
+	EventScheduleChanged  = "schedule-changed"
+	EventStartingSoon     = "event-starting-soon"
+	EventFavouriteChanged = "favourite-changed"
+	EventResync           = "resync"
+)
+
+// ScheduleChanged is sent when a conference's schedule has been refreshed
+// and differs from before; clients should fetch it again.
+type ScheduleChanged struct {
+	ChangedAt time.Time `json:"changedAt"`
+}
+
+// FavouriteChanged is sent to a user when their favourites in a conference
+// were changed, possibly from another device.
+type FavouriteChanged struct {
+	ConferenceID int32 `json:"conferenceId"`
+}
+
+const (
+	// how many past messages are kept for each conference so clients can
+	// catch up on anything missed while reconnecting
+	historySize = 256
+	// how many messages can be waiting for a slow client before it is
+	// disconnected; it can then reconnect and resume from where it got to
+	subscriberBuffer = 32
+	// how far ahead to announce events which are about to start
+	startingSoonWindow = 10 * time.Minute
+)
+
+// Subscription receives messages for a conference until it is unsubscribed
+// or falls too far behind, at which point Messages is closed.
+type Subscription struct {
+	Messages     <-chan Message
+	messages     chan Message
+	conferenceID int32
+	userID       int32
+}
+
+type conferenceChannel struct {
+	subscribers map[*Subscription]bool
+	history     []Message
+	// the newest message dropped from history; clients which have not seen
+	// it can't catch up
+	dropped uint64
+	// events already announced as starting soon, keyed by event and start
+	// time so that rescheduled events are announced again
+	announced map[string]time.Time
+	// the checksum of the last schedule clients were told about
+	checksum string
+}
+
+type hub struct {
+	conferenceService conference.Service
+	conferences       map[int32]*conferenceChannel
+	firstID           uint64
+	nextID            uint64
+	lock              sync.Mutex
+}
+
+func NewHub(conferenceService conference.Service) Hub {
+	// start IDs from the current time so IDs handed out before a restart
+	// are never mistaken for new ones
+	firstID := uint64(time.Now().UnixMilli()) * 1000
+	h := &hub{
+		conferenceService: conferenceService,
+		conferences:       make(map[int32]*conferenceChannel),
+		firstID:           firstID,
+		nextID:            firstID,
+	}
+	go h.announceStartingEvents()
+	return h
+}
+
+func (h *hub) channel(conferenceID int32) *conferenceChannel {
+	c, ok := h.conferences[conferenceID]
+	if !ok {
+		c = &conferenceChannel{
+			subscribers: make(map[*Subscription]bool),
+			announced:   make(map[string]time.Time),
+		}
+		h.conferences[conferenceID] = c
+	}
+	return c
+}
+
+// Subscribe starts receiving messages for a conference. If lastEventID is
+// given, any messages since then are returned so the client can catch up;
+// if they are no longer available a single resync message is returned
+// instead and the client should fetch everything again.
+func (h *hub) Subscribe(conferenceID int32, userID int32, lastEventID string) (*Subscription, []Message) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	messages := make(chan Message, subscriberBuffer)
+	subscription := &Subscription{
+		Messages:     messages,
+		messages:     messages,
+		conferenceID: conferenceID,
+		userID:       userID,
+	}
+
+	c := h.channel(conferenceID)
+	c.subscribers[subscription] = true
+
+	if lastEventID == "" {
+		return subscription, nil
+	}
+
+	last, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil || last < h.firstID || last < c.dropped || last > h.nextID {
+		return subscription, []Message{h.message(conferenceID, userID, EventResync, nil)}
+	}
+
+	var missed []Message
+	for _, message := range c.history {
+		if message.ID > last && subscription.wants(message) {
+			missed = append(missed, message)
+		}
+	}
+	return subscription, missed
+}
+
+func (h *hub) Unsubscribe(subscription *Subscription) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.unsubscribe(subscription)
+}
+
+// unsubscribe must be called with h.lock held
+func (h *hub) unsubscribe(subscription *Subscription) {
+	c, ok := h.conferences[subscription.conferenceID]
+	if !ok || !c.subscribers[subscription] {
+		return
+	}
+
+	delete(c.subscribers, subscription)
+	close(subscription.messages)
+}
+
+func (h *hub) Publish(conferenceID int32, userID int32, event string, data any) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.publish(h.message(conferenceID, userID, event, data))
+}
+
+// PublishSchedule sends EventScheduleChanged if the schedule differs from
+// the last one published for the conference. Schedules are also refreshed
+// without anything in them changing, which clients needn't refetch.
+func (h *hub) PublishSchedule(conferenceID int32, schedule *conference.Schedule) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	c := h.channel(conferenceID)
+	if c.checksum == schedule.Checksum {
+		return
+	}
+	c.checksum = schedule.Checksum
+
+	h.publish(h.message(conferenceID, 0, EventScheduleChanged, ScheduleChanged{ChangedAt: time.Now()}))
+}
+
+// message must be called with h.lock held
+func (h *hub) message(conferenceID int32, userID int32, event string, data any) Message {
+	encoded, err := json.Marshal(data)
+	if err != nil || data == nil {
+		encoded = []byte("{}")
+	}
+
+	h.nextID++
+	return Message{
+		ID:           h.nextID,
+		Event:        event,
+		Data:         encoded,
+		ConferenceID: conferenceID,
+		UserID:       userID,
+	}
+}
+
+// publish must be called with h.lock held
+func (h *hub) publish(message Message) {
+	c := h.channel(message.ConferenceID)
+
+	c.history = append(c.history, message)
+	if len(c.history) > historySize {
+		c.dropped = c.history[0].ID
+		c.history = c.history[1:]
+	}
+
+	for subscription := range c.subscribers {
+		if !subscription.wants(message) {
+			continue
+		}
+		select {
+		case subscription.messages <- message:
+		default:
+			h.unsubscribe(subscription)
+		}
+	}
+}
+
+func (s *Subscription) wants(message Message) bool {
+	return message.UserID == 0 || message.UserID == s.userID
+}
+
+func (h *hub) announceStartingEvents() {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		h.lock.Lock()
+		var conferenceIDs []int32
+		for conferenceID, c := range h.conferences {
+			if len(c.subscribers) > 0 {
+				conferenceIDs = append(conferenceIDs, conferenceID)
+			}
+		}
+		h.lock.Unlock()
+
+		for _, conferenceID := range conferenceIDs {
+			page, err := h.conferenceService.GetEvents(conferenceID, conference.EventFilter{
+				StartingWithin: startingSoonWindow,
+			}, "", 0)
+			if err != nil {
+				continue
+			}
+
+			h.lock.Lock()
+			c := h.channel(conferenceID)
+			for _, event := range page.Events {
+				key := strconv.Itoa(int(event.ID)) + "@" + strconv.FormatInt(event.Start.Unix(), 10)
+				if _, ok := c.announced[key]; ok {
+					continue
+				}
+				c.announced[key] = event.Start
+				h.publish(h.message(conferenceID, 0, EventStartingSoon, event))
+			}
+			for key, start := range c.announced {
+				if start.Before(time.Now()) {
+					delete(c.announced, key)
+				}
+			}
+			h.lock.Unlock()
+		}
+	}
+}
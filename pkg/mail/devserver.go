@@ -0,0 +1,157 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// DevServer is a minimal SMTP server which accepts every message and writes
+// it to the log. It stands in for a real relay during development, so that
+// mail still goes through the SMTP sender. It speaks plain SMTP only, so
+// senders must be configured with TLSNone, and accepts any credentials.
+type DevServer struct {
+	listener net.Listener
+}
+
+// NewDevServer starts a DevServer listening on address, such as
+// "127.0.0.1:2525". A port of 0 picks a free one.
+func NewDevServer(address string) (*DevServer, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("could not listen for SMTP: %w", err)
+	}
+
+	s := &DevServer{listener: listener}
+	go s.serve()
+	return s, nil
+}
+
+// Addr is the address the server is listening on.
+func (s *DevServer) Addr() *net.TCPAddr {
+	return s.listener.Addr().(*net.TCPAddr)
+}
+
+func (s *DevServer) Close() error {
+	return s.listener.Close()
+}
+
+func (s *DevServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *DevServer) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Minute))
+
+	text := textproto.NewConn(conn)
+	reply := func(code int, message string) bool {
+		return text.PrintfLine("%d %s", code, message) == nil
+	}
+
+	if !reply(220, "localhost confplanner development SMTP server") {
+		return
+	}
+
+	var from string
+	var to []string
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+
+		var ok bool
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			ok = text.PrintfLine("250-localhost") == nil && reply(250, "AUTH PLAIN")
+		case "HELO":
+			ok = reply(250, "localhost")
+		case "AUTH":
+			ok = reply(235, "Authentication succeeded")
+		case "MAIL":
+			from, to = strings.TrimPrefix(arg, "FROM:"), nil
+			ok = reply(250, "OK")
+		case "RCPT":
+			to = append(to, strings.TrimPrefix(arg, "TO:"))
+			ok = reply(250, "OK")
+		case "DATA":
+			if !reply(354, "End data with <CR><LF>.<CR><LF>") {
+				return
+			}
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			logMessage(from, to, data)
+			ok = reply(250, "OK")
+		case "RSET":
+			from, to = "", nil
+			ok = reply(250, "OK")
+		case "NOOP":
+			ok = reply(250, "OK")
+		case "QUIT":
+			reply(221, "Bye")
+			return
+		default:
+			ok = reply(502, "Command not implemented")
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// logMessage writes a received message to the log, along with its plain
+// text body so that links in it can be followed.
+func logMessage(from string, to []string, data []byte) {
+	message, err := mail.ReadMessage(bufio.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		slog.Error("could not read email", "error", err)
+		return
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	if err != nil {
+		subject = message.Header.Get("Subject")
+	}
+
+	slog.Info("email", "from", from, "to", strings.Join(to, ", "), "subject", subject, "body", plainText(message))
+}
+
+func plainText(message *mail.Message) string {
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		body, _ := io.ReadAll(message.Body)
+		return string(body)
+	}
+
+	// parts are decoded from quoted-printable as they are read
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return ""
+		}
+		if strings.HasPrefix(part.Header.Get("Content-Type"), "text/plain") {
+			body, _ := io.ReadAll(part)
+			return string(body)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LMBishop/confplanner/internal/random"
+)
+
+// Message is an email with both a plain text and an HTML body.
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type Sender interface {
+	Send(message Message) error
+}
+
+const (
+	TLSNone     = "none"
+	TLSStartTLS = "starttls"
+	TLSImplicit = "tls"
+)
+
+type smtpSender struct {
+	host     string
+	port     int
+	username string
+	password string
+	tlsMode  string
+}
+
+// NewSMTPSender returns a sender which delivers mail through an SMTP relay.
+// tlsMode is one of TLSNone, TLSStartTLS (the default) or TLSImplicit.
+func NewSMTPSender(host string, port int, username string, password string, tlsMode string) Sender {
+	if tlsMode == "" {
+		tlsMode = TLSStartTLS
+	}
+	if port == 0 {
+		switch tlsMode {
+		case TLSImplicit:
+			port = 465
+		case TLSStartTLS:
+			port = 587
+		default:
+			port = 25
+		}
+	}
+
+	return &smtpSender{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		tlsMode:  tlsMode,
+	}
+}
+
+func (s *smtpSender) Send(message Message) error {
+	from, err := mail.ParseAddress(message.From)
+	if err != nil {
+		return fmt.Errorf("bad from address: %w", err)
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("bad to address: %w", err)
+	}
+
+	body, err := build(message)
+	if err != nil {
+		return err
+	}
+
+	address := net.JoinHostPort(s.host, strconv.Itoa(s.port))
+	tlsConfig := &tls.Config{ServerName: s.host}
+
+	var conn net.Conn
+	if s.tlsMode == TLSImplicit {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: 30 * time.Second}, "tcp", address, tlsConfig)
+	} else {
+		conn, err = net.DialTimeout("tcp", address, 30*time.Second)
+	}
+	if err != nil {
+		return fmt.Errorf("could not connect to SMTP server: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(time.Minute))
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("could not connect to SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	if s.tlsMode == TLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("could not start TLS: %w", err)
+		}
+	}
+
+	if s.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return fmt.Errorf("could not authenticate with SMTP server: %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("SMTP server rejected sender: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("SMTP server rejected recipient: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("could not send message: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("could not send message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("could not send message: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// NewLogSender returns a sender which writes messages to the log instead of
+// delivering them. Messages still go through the SMTP sender, to a
+// DevServer started on a free loopback port.
+func NewLogSender() (Sender, error) {
+	server, err := NewDevServer("127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSMTPSender("127.0.0.1", server.Addr().Port, "", "", TLSNone), nil
+}
+
+// build renders a message as a multipart/alternative MIME message.
+func build(message Message) ([]byte, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	id, err := random.String(24)
+	if err != nil {
+		return nil, err
+	}
+	domain := "localhost"
+	if from, err := mail.ParseAddress(message.From); err == nil {
+		if _, d, ok := strings.Cut(from.Address, "@"); ok {
+			domain = d
+		}
+	}
+
+	header := []string{
+		"From: " + message.From,
+		"To: " + message.To,
+		"Subject: " + mime.QEncoding.Encode("utf-8", message.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: <" + id + "@" + domain + ">",
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + w.Boundary(),
+	}
+	buf.WriteString(strings.Join(header, "\r\n") + "\r\n\r\n")
+
+	for _, part := range []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	} {
+		pw, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,471 @@
+package mail
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LMBishop/confplanner/internal/random"
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/LMBishop/confplanner/pkg/favourites"
+	"github.com/LMBishop/confplanner/pkg/user"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Service interface {
+	Enabled() bool
+	RequestVerification(id int32, email string) error
+	VerifyEmail(token string) error
+	RemoveEmail(id int32) error
+	GetDigestsForUser(id int32) ([]sqlc.EmailDigest, error)
+	SendDigests() error
+	NotifyScheduleChanges(update conference.ScheduleUpdate) error
+}
+
+var (
+	ErrMailDisabled   = errors.New("email is not configured")
+	ErrInvalidAddress = errors.New("invalid email address")
+	ErrInvalidToken   = errors.New("invalid or expired verification token")
+	ErrEmailNotFound  = errors.New("no email address set")
+)
+
+const verificationExpiry = 24 * time.Hour
+
+// how long after the digest hour a digest may still be sent, so one is not
+// missed if the server was briefly down, but nobody gets their morning
+// digest in the evening
+const digestWindow = 3 * time.Hour
+
+type service struct {
+	pool              *pgxpool.Pool
+	sender            Sender
+	userService       user.Service
+	favouritesService favourites.Service
+	conferenceService conference.Service
+	from              string
+	baseURL           string
+	digestHour        int
+}
+
+// NewService creates the mailer. If sender is nil, email is disabled and
+// nothing is sent. Otherwise a digest of the day's favourites is sent to
+// each user from digestHour in their own time zone.
+func NewService(pool *pgxpool.Pool, sender Sender, userService user.Service, favouritesService favourites.Service, conferenceService conference.Service, from string, baseURL string, digestHour int) Service {
+	s := &service{
+		pool:              pool,
+		sender:            sender,
+		userService:       userService,
+		favouritesService: favouritesService,
+		conferenceService: conferenceService,
+		from:              from,
+		baseURL:           baseURL,
+		digestHour:        digestHour,
+	}
+	if sender != nil {
+		go s.runDigests()
+	}
+	return s
+}
+
+func (s *service) Enabled() bool {
+	return s.sender != nil
+}
+
+// RequestVerification emails a link the user must follow before email is
+// sent to the address. Their current address, if any, is kept until then.
+func (s *service) RequestVerification(id int32, email string) error {
+	if s.sender == nil {
+		return ErrMailDisabled
+	}
+
+	address, err := mail.ParseAddress(email)
+	if err != nil {
+		return ErrInvalidAddress
+	}
+
+	u, err := s.userService.GetUserByID(id)
+	if err != nil {
+		return err
+	}
+
+	token, err := random.String(48)
+	if err != nil {
+		return err
+	}
+
+	queries := sqlc.New(s.pool)
+
+	_, err = queries.CreateEmailVerification(context.Background(), sqlc.CreateEmailVerificationParams{
+		UserID:    id,
+		Email:     address.Address,
+		TokenHash: hashToken(token),
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  time.Now().Add(verificationExpiry),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("could not create verification: %w", err)
+	}
+
+	return s.send(address.Address, "Confirm your email address", "verify", verifyData{
+		Username:  u.Username,
+		Email:     address.Address,
+		Link:      s.baseURL + "/verify-email?token=" + token,
+		ExpiresIn: "24 hours",
+	})
+}
+
+func (s *service) VerifyEmail(token string) error {
+	ctx := context.Background()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	queries := sqlc.New(s.pool).WithTx(tx)
+
+	verification, err := queries.GetEmailVerificationByToken(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrInvalidToken
+		}
+		return fmt.Errorf("could not fetch verification: %w", err)
+	}
+	if verification.ExpiresAt.Time.Before(time.Now()) {
+		return ErrInvalidToken
+	}
+
+	err = queries.SetUserEmail(ctx, sqlc.SetUserEmailParams{
+		UserID: verification.UserID,
+		Email: pgtype.Text{
+			String: verification.Email,
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("could not update email: %w", err)
+	}
+
+	if err := queries.DeleteEmailVerification(ctx, verification.UserID); err != nil {
+		return fmt.Errorf("could not delete verification: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) RemoveEmail(id int32) error {
+	queries := sqlc.New(s.pool)
+
+	if err := queries.DeleteEmailVerification(context.Background(), id); err != nil {
+		return fmt.Errorf("could not delete verification: %w", err)
+	}
+
+	rowsAffected, err := queries.ClearUserEmail(context.Background(), id)
+	if err != nil {
+		return fmt.Errorf("could not remove email: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrEmailNotFound
+	}
+
+	return nil
+}
+
+// GetDigestsForUser returns a record of every digest sent to the user.
+func (s *service) GetDigestsForUser(id int32) ([]sqlc.EmailDigest, error) {
+	queries := sqlc.New(s.pool)
+
+	digests, err := queries.GetEmailDigestsForUser(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch digests: %w", err)
+	}
+
+	return digests, nil
+}
+
+func (s *service) runDigests() {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.SendDigests(); err != nil {
+			slog.Error("failed to send email digests", "error", err)
+		}
+	}
+}
+
+// SendDigests emails each user who wants one a summary of their favourites
+// happening today, once per conference per day.
+func (s *service) SendDigests() error {
+	if s.sender == nil {
+		return ErrMailDisabled
+	}
+
+	queries := sqlc.New(s.pool)
+
+	recipients, err := queries.GetDigestRecipients(context.Background())
+	if err != nil {
+		return fmt.Errorf("could not fetch recipients: %w", err)
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	conferences, err := s.conferenceService.GetConferences(conference.ConferenceFilter{
+		Visibilities: []string{conference.VisibilityPublished},
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, profile := range recipients {
+		location := user.Location(&profile)
+		now := time.Now().In(location)
+		digestStart := time.Date(now.Year(), now.Month(), now.Day(), s.digestHour, 0, 0, 0, location)
+		if now.Before(digestStart) || !now.Before(digestStart.Add(digestWindow)) {
+			continue
+		}
+
+		for _, c := range conferences {
+			if err := s.sendDigest(profile, c.ID, now); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *service) sendDigest(profile sqlc.UserProfile, conferenceID int32, now time.Time) error {
+	events, err := s.favouritesService.GetFavouriteEventsForUserConference(profile.UserID, conferenceID)
+	if err != nil {
+		if errors.Is(err, conference.ErrConferenceNotFound) {
+			// the user can no longer access the conference
+			return nil
+		}
+		return err
+	}
+
+	location := now.Location()
+	var today []favourites.FavouriteEvent
+	for _, event := range events {
+		start := event.Event.Start.In(location)
+		if start.Year() == now.Year() && start.YearDay() == now.YearDay() {
+			today = append(today, event)
+		}
+	}
+	if len(today) == 0 {
+		return nil
+	}
+	sort.Slice(today, func(i, j int) bool {
+		return today[i].Event.Start.Before(today[j].Event.Start)
+	})
+
+	queries := sqlc.New(s.pool)
+
+	// the digest is recorded before sending so that it is only sent once,
+	// and forgotten again if sending fails so that it is retried
+	digest := sqlc.CreateEmailDigestParams{
+		UserID:       profile.UserID,
+		ConferenceID: conferenceID,
+		Day: pgtype.Date{
+			Time:  time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+			Valid: true,
+		},
+	}
+	created, err := queries.CreateEmailDigest(context.Background(), digest)
+	if err != nil {
+		return fmt.Errorf("could not record digest: %w", err)
+	}
+	if created == 0 {
+		return nil
+	}
+
+	layout := user.TimeLayout(&profile)
+	data := digestData{
+		Conference: s.conferenceTitle(conferenceID),
+		Date:       now.Format("Monday 2 January"),
+		Link:       s.baseURL + "/agenda",
+	}
+	for _, event := range today {
+		data.Events = append(data.Events, digestEvent{
+			Start: event.Event.Start.In(location).Format(layout),
+			End:   event.Event.End.In(location).Format(layout),
+			Title: event.Event.Title,
+			Room:  event.Event.Room,
+			Note:  event.Favourite.Note.String,
+		})
+	}
+
+	if err := s.send(profile.Email.String, "Your agenda for today", "digest", data); err != nil {
+		if deleteErr := queries.DeleteEmailDigest(context.Background(), sqlc.DeleteEmailDigestParams(digest)); deleteErr != nil {
+			return errors.Join(err, fmt.Errorf("could not forget digest: %w", deleteErr))
+		}
+		return err
+	}
+
+	return nil
+}
+
+// NotifyScheduleChanges emails users whose favourites were moved or
+// cancelled by a schedule update.
+func (s *service) NotifyScheduleChanges(update conference.ScheduleUpdate) error {
+	if update.Changes == nil || s.sender == nil {
+		return nil
+	}
+	conferenceID := update.ConferenceID
+
+	type change struct {
+		before conference.Event
+		after  *conference.Event
+	}
+	changes := make(map[string]change)
+	for _, c := range update.Changes.Changed {
+		// a new title alone isn't worth an email
+		if c.Before.Start.Equal(c.After.Start) && c.Before.End.Equal(c.After.End) && c.Before.Room == c.After.Room {
+			continue
+		}
+		after := c.After
+		changes[eventKey(c.Before.GUID, c.Before.ID)] = change{before: c.Before, after: &after}
+	}
+	for _, event := range update.Changes.Removed {
+		changes[eventKey(event.GUID, event.ID)] = change{before: event}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	queries := sqlc.New(s.pool)
+
+	recipients, err := queries.GetAlertRecipientsForConference(context.Background(), conferenceID)
+	if err != nil {
+		return fmt.Errorf("could not fetch recipients: %w", err)
+	}
+
+	type userAlert struct {
+		profile sqlc.UserProfile
+		changes []change
+	}
+	alerts := make(map[int32]*userAlert)
+	var order []int32
+	for _, recipient := range recipients {
+		var guid string
+		if recipient.EventGuid.Valid {
+			guid = recipient.EventGuid.String()
+		}
+		c, ok := changes[eventKey(guid, recipient.EventID.Int32)]
+		if !ok {
+			continue
+		}
+
+		alert, ok := alerts[recipient.UserID]
+		if !ok {
+			alert = &userAlert{
+				profile: sqlc.UserProfile{
+					UserID:   recipient.UserID,
+					Email:    recipient.Email,
+					TimeZone: recipient.TimeZone,
+					Clock:    recipient.Clock,
+				},
+			}
+			alerts[recipient.UserID] = alert
+			order = append(order, recipient.UserID)
+		}
+		alert.changes = append(alert.changes, c)
+	}
+
+	title := update.Schedule.Conference.Title
+	var errs []error
+	for _, userID := range order {
+		alert := alerts[userID]
+		location := user.Location(&alert.profile)
+		layout := user.TimeLayout(&alert.profile)
+
+		data := alertData{
+			Conference: title,
+			Link:       s.baseURL + "/agenda",
+		}
+		for _, c := range alert.changes {
+			ac := alertChange{
+				Title:         c.before.Title,
+				Cancelled:     c.after == nil,
+				PreviousDay:   c.before.Start.In(location).Format("Mon 2 Jan"),
+				PreviousStart: c.before.Start.In(location).Format(layout),
+				PreviousRoom:  c.before.Room,
+			}
+			if c.after != nil {
+				ac.Day = c.after.Start.In(location).Format("Mon 2 Jan")
+				ac.Start = c.after.Start.In(location).Format(layout)
+				ac.End = c.after.End.In(location).Format(layout)
+				ac.Room = c.after.Room
+			}
+			data.Changes = append(data.Changes, ac)
+		}
+
+		if err := s.send(alert.profile.Email.String, "Changes to your agenda", "alert", data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *service) send(to string, subject string, template string, data any) error {
+	text, html, err := render(template, data)
+	if err != nil {
+		return fmt.Errorf("could not render %s email: %w", template, err)
+	}
+
+	if err := s.sender.Send(Message{
+		From:    s.from,
+		To:      to,
+		Subject: subject,
+		Text:    text,
+		HTML:    html,
+	}); err != nil {
+		return fmt.Errorf("could not send %s email: %w", template, err)
+	}
+
+	return nil
+}
+
+func (s *service) conferenceTitle(conferenceID int32) string {
+	schedule, _, err := s.conferenceService.GetSchedule(conferenceID)
+	if err != nil || schedule.Conference.Title == "" {
+		return "your conference"
+	}
+	return schedule.Conference.Title
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// eventKey identifies an event the same way in schedules and favourites,
+// which store GUIDs in their canonical lower case form.
+func eventKey(guid string, id int32) string {
+	if guid != "" {
+		return strings.ToLower(guid)
+	}
+	return strconv.Itoa(int(id))
+}
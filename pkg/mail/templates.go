@@ -0,0 +1,68 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var (
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/*.txt"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/*.html"))
+)
+
+// render fills in the plain text and HTML versions of a template.
+func render(name string, data any) (string, string, error) {
+	var text, html bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return "", "", err
+	}
+	if err := htmlTemplates.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return "", "", err
+	}
+	return text.String(), html.String(), nil
+}
+
+type verifyData struct {
+	Username  string
+	Email     string
+	Link      string
+	ExpiresIn string
+}
+
+type digestData struct {
+	Conference string
+	Date       string
+	Events     []digestEvent
+	Link       string
+}
+
+type digestEvent struct {
+	Start string
+	End   string
+	Title string
+	Room  string
+	Note  string
+}
+
+type alertData struct {
+	Conference string
+	Changes    []alertChange
+	Link       string
+}
+
+type alertChange struct {
+	Title         string
+	Cancelled     bool
+	Day           string
+	Start         string
+	End           string
+	Room          string
+	PreviousDay   string
+	PreviousStart string
+	PreviousRoom  string
+}
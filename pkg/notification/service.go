@@ -0,0 +1,125 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Service interface {
+	Notify(userIDs []int32, notification Notification) error
+	GetNotificationsForUser(id int32, unreadOnly bool, limit int32) ([]sqlc.Notification, error)
+	MarkRead(id int32, notificationID int32) error
+	MarkAllRead(id int32) (int64, error)
+}
+
+// Notification is a message to be delivered to one or more users.
+type Notification struct {
+	ConferenceID int32
+	Kind         string
+	Title        string
+	Body         string
+	EventGUID    pgtype.UUID
+}
+
+const (
+	KindSpeakerEventAdded     = "speaker-event-added"
+	KindSpeakerEventChanged   = "speaker-event-changed"
+	KindSpeakerEventCancelled = "speaker-event-cancelled"
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+type service struct {
+	pool *pgxpool.Pool
+}
+
+func NewService(pool *pgxpool.Pool) Service {
+	return &service{
+		pool: pool,
+	}
+}
+
+func (s *service) Notify(userIDs []int32, notification Notification) error {
+	queries := sqlc.New(s.pool)
+
+	var conferenceID pgtype.Int4
+	if notification.ConferenceID != 0 {
+		conferenceID = pgtype.Int4{
+			Int32: notification.ConferenceID,
+			Valid: true,
+		}
+	}
+
+	for _, userID := range userIDs {
+		if _, err := queries.CreateNotification(context.Background(), sqlc.CreateNotificationParams{
+			UserID:       userID,
+			ConferenceID: conferenceID,
+			Kind:         notification.Kind,
+			Title:        notification.Title,
+			Body:         notification.Body,
+			EventGuid:    notification.EventGUID,
+		}); err != nil {
+			return fmt.Errorf("could not create notification: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) GetNotificationsForUser(id int32, unreadOnly bool, limit int32) ([]sqlc.Notification, error) {
+	queries := sqlc.New(s.pool)
+
+	var notifications []sqlc.Notification
+	var err error
+	if unreadOnly {
+		notifications, err = queries.GetUnreadNotificationsForUser(context.Background(), sqlc.GetUnreadNotificationsForUserParams{
+			UserID: id,
+			Limit:  limit,
+		})
+	} else {
+		notifications, err = queries.GetNotificationsForUser(context.Background(), sqlc.GetNotificationsForUserParams{
+			UserID: id,
+			Limit:  limit,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+func (s *service) MarkRead(id int32, notificationID int32) error {
+	queries := sqlc.New(s.pool)
+
+	rowsAffected, err := queries.MarkNotificationRead(context.Background(), sqlc.MarkNotificationReadParams{
+		ID:     notificationID,
+		UserID: id,
+	})
+	if err != nil {
+		return fmt.Errorf("could not update notification: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+func (s *service) MarkAllRead(id int32) (int64, error) {
+	queries := sqlc.New(s.pool)
+
+	rowsAffected, err := queries.MarkAllNotificationsRead(context.Background(), id)
+	if err != nil {
+		return 0, fmt.Errorf("could not update notifications: %w", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,151 @@
+package popularity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Service interface {
+	GetPopularityForConference(conferenceID int32) ([]EventPopularity, time.Time, error)
+	Threshold() int
+}
+
+// EventPopularity is the number of users who have favourited an event.
+type EventPopularity struct {
+	Event      conference.Event
+	Favourites int64
+}
+
+type cachedPopularity struct {
+	favourites  []sqlc.GetFavouriteUsersForConferenceRow
+	lastUpdated time.Time
+}
+
+type service struct {
+	pool              *pgxpool.Pool
+	conferenceService conference.Service
+	threshold         int
+	refreshInterval   time.Duration
+	cache             map[int32]*cachedPopularity
+	lock              sync.Mutex
+}
+
+// NewService creates a popularity service which only reports events with at
+// least threshold favourites, so that no individual user's choices can be
+// picked out of the counts. Counts are recalculated at most once every
+// refreshInterval.
+func NewService(pool *pgxpool.Pool, conferenceService conference.Service, threshold int, refreshInterval time.Duration) Service {
+	if threshold < 1 {
+		threshold = 5
+	}
+	if refreshInterval <= 0 {
+		refreshInterval = 5 * time.Minute
+	}
+	return &service{
+		pool:              pool,
+		conferenceService: conferenceService,
+		threshold:         threshold,
+		refreshInterval:   refreshInterval,
+		cache:             make(map[int32]*cachedPopularity),
+	}
+}
+
+func (s *service) Threshold() int {
+	return s.threshold
+}
+
+func (s *service) GetPopularityForConference(conferenceID int32) ([]EventPopularity, time.Time, error) {
+	// make sure the conference exists and its schedule is loaded before
+	// resolving events
+	if _, _, err := s.conferenceService.GetSchedule(conferenceID); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	cached, err := s.getCounts(conferenceID)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	// the same event may be favourited under more than one (guid, id)
+	// pair if some favourites pre-date the event's guid being known, so
+	// users are counted once per event rather than once per favourite
+	events := make(map[string]conference.Event)
+	users := make(map[string]map[int32]bool)
+	for _, favourite := range cached.favourites {
+		var event *conference.Event
+		var err error
+		if favourite.EventGuid.Valid {
+			event, err = s.conferenceService.GetEventByGUID(conferenceID, favourite.EventGuid.String(), 0)
+		} else {
+			event, err = s.conferenceService.GetEventByID(conferenceID, favourite.EventID.Int32)
+		}
+		if errors.Is(err, conference.ErrEventNotFound) {
+			continue
+		} else if err != nil {
+			return nil, time.Time{}, err
+		}
+		if event.Custom != nil && !event.Custom.Shared {
+			// personal events are only visible to their owner
+			continue
+		}
+
+		key := event.Key()
+		if _, ok := users[key]; !ok {
+			events[key] = *event
+			users[key] = make(map[int32]bool)
+		}
+		users[key][favourite.UserID] = true
+	}
+
+	popularity := make([]EventPopularity, 0, len(events))
+	for key, event := range events {
+		if len(users[key]) < s.threshold {
+			continue
+		}
+		popularity = append(popularity, EventPopularity{
+			Event:      event,
+			Favourites: int64(len(users[key])),
+		})
+	}
+	sort.Slice(popularity, func(i, j int) bool {
+		if popularity[i].Favourites != popularity[j].Favourites {
+			return popularity[i].Favourites > popularity[j].Favourites
+		}
+		return popularity[i].Event.Start.Before(popularity[j].Event.Start)
+	})
+
+	return popularity, cached.lastUpdated, nil
+}
+
+func (s *service) getCounts(conferenceID int32) (*cachedPopularity, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	cached, ok := s.cache[conferenceID]
+	if ok && time.Since(cached.lastUpdated) < s.refreshInterval {
+		return cached, nil
+	}
+
+	queries := sqlc.New(s.pool)
+
+	favourites, err := queries.GetFavouriteUsersForConference(context.Background(), conferenceID)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch favourites: %w", err)
+	}
+
+	cached = &cachedPopularity{
+		favourites:  favourites,
+		lastUpdated: time.Now(),
+	}
+	s.cache[conferenceID] = cached
+
+	return cached, nil
+}
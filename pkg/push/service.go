@@ -0,0 +1,331 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/LMBishop/confplanner/pkg/favourites"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Service interface {
+	PublicKey() string
+	Subscribe(id int32, subscription Subscription) (*sqlc.PushSubscription, error)
+	Unsubscribe(id int32, endpoint string) error
+	GetSubscriptionsForUser(id int32) ([]sqlc.PushSubscription, error)
+	SendToUser(id int32, message Message) error
+	SendReminders() error
+}
+
+// Subscription is the endpoint and keys a browser gives when subscribing to
+// push messages.
+type Subscription struct {
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+// Message is the payload shown by the service worker as a notification.
+type Message struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url,omitempty"`
+	// notifications with the same tag replace each other
+	Tag string `json:"tag,omitempty"`
+}
+
+var (
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrBadEndpoint          = errors.New("bad push endpoint")
+)
+
+type service struct {
+	pool              *pgxpool.Pool
+	favouritesService favourites.Service
+	conferenceService conference.Service
+	keys              *vapidKeys
+	subject           string
+	reminderLead      time.Duration
+	// allow plain HTTP endpoints, for testing against a local stand-in for
+	// a push service
+	allowInsecure bool
+	client        *http.Client
+}
+
+// NewService loads the server's VAPID keys, generating and storing them on
+// first use, and starts sending reminders for favourites reminderLead
+// before they begin. subject is a mailto: or https: URL push services can
+// use to contact the operator.
+func NewService(pool *pgxpool.Pool, favouritesService favourites.Service, conferenceService conference.Service, subject string, reminderLead time.Duration, allowInsecure bool) (Service, error) {
+	keys, err := loadVapidKeys(pool)
+	if err != nil {
+		return nil, err
+	}
+
+	if reminderLead <= 0 {
+		reminderLead = 5 * time.Minute
+	}
+
+	s := &service{
+		pool:              pool,
+		favouritesService: favouritesService,
+		conferenceService: conferenceService,
+		keys:              keys,
+		subject:           subject,
+		reminderLead:      reminderLead,
+		allowInsecure:     allowInsecure,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+	go s.runReminders()
+
+	return s, nil
+}
+
+func loadVapidKeys(pool *pgxpool.Pool) (*vapidKeys, error) {
+	queries := sqlc.New(pool)
+
+	stored, err := queries.GetVapidKeys(context.Background())
+	if err == nil {
+		keys, err := parseVapidKeys(stored.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse VAPID keys: %w", err)
+		}
+		return keys, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("could not fetch VAPID keys: %w", err)
+	}
+
+	keys, private, err := generateVapidKeys()
+	if err != nil {
+		return nil, fmt.Errorf("could not generate VAPID keys: %w", err)
+	}
+
+	// another instance may have got there first, in which case its keys
+	// are returned and used instead
+	stored, err = queries.CreateVapidKeys(context.Background(), sqlc.CreateVapidKeysParams{
+		PrivateKey: private,
+		PublicKey:  keys.public,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not store VAPID keys: %w", err)
+	}
+	if stored.PrivateKey != private {
+		return parseVapidKeys(stored.PrivateKey)
+	}
+
+	return keys, nil
+}
+
+func (s *service) PublicKey() string {
+	return s.keys.public
+}
+
+func (s *service) Subscribe(id int32, subscription Subscription) (*sqlc.PushSubscription, error) {
+	endpoint, err := url.Parse(subscription.Endpoint)
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "https" && !(s.allowInsecure && endpoint.Scheme == "http")) {
+		return nil, ErrBadEndpoint
+	}
+
+	// check the keys are usable now rather than failing on every message
+	if _, err := encrypt(nil, subscription.P256dh, subscription.Auth); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadEndpoint, err)
+	}
+
+	queries := sqlc.New(s.pool)
+
+	stored, err := queries.UpsertPushSubscription(context.Background(), sqlc.UpsertPushSubscriptionParams{
+		UserID:   id,
+		Endpoint: subscription.Endpoint,
+		P256dh:   subscription.P256dh,
+		Auth:     subscription.Auth,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create subscription: %w", err)
+	}
+
+	return &stored, nil
+}
+
+func (s *service) Unsubscribe(id int32, endpoint string) error {
+	queries := sqlc.New(s.pool)
+
+	rowsAffected, err := queries.DeletePushSubscription(context.Background(), sqlc.DeletePushSubscriptionParams{
+		UserID:   id,
+		Endpoint: endpoint,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete subscription: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+func (s *service) GetSubscriptionsForUser(id int32) ([]sqlc.PushSubscription, error) {
+	queries := sqlc.New(s.pool)
+
+	subscriptions, err := queries.GetPushSubscriptionsForUser(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// SendToUser sends a message to every device the user has subscribed.
+// Subscriptions the push service says have expired are removed.
+func (s *service) SendToUser(id int32, message Message) error {
+	queries := sqlc.New(s.pool)
+
+	subscriptions, err := queries.GetPushSubscriptionsForUser(context.Background(), id)
+	if err != nil {
+		return fmt.Errorf("could not fetch subscriptions: %w", err)
+	}
+	if len(subscriptions) == 0 {
+		return ErrSubscriptionNotFound
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, subscription := range subscriptions {
+		body, err := encrypt(payload, subscription.P256dh, subscription.Auth)
+		if err == nil {
+			err = send(s.client, s.keys, s.subject, subscription.Endpoint, body)
+		}
+		if errors.Is(err, errSubscriptionGone) {
+			if err := queries.DeletePushSubscriptionByEndpoint(context.Background(), subscription.Endpoint); err != nil {
+				errs = append(errs, fmt.Errorf("could not delete subscription: %w", err))
+			}
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not send to %s: %w", subscription.Endpoint, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *service) runReminders() {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.SendReminders(); err != nil {
+			slog.Error("failed to send push reminders", "error", err)
+		}
+	}
+}
+
+// SendReminders notifies users with push subscriptions about any of their
+// favourites starting within the reminder lead time. Each favourite is only
+// reminded about once for a given start time, so a rescheduled event is
+// reminded about again.
+func (s *service) SendReminders() error {
+	queries := sqlc.New(s.pool)
+
+	users, err := queries.GetUsersWithPushSubscriptions(context.Background())
+	if err != nil {
+		return fmt.Errorf("could not fetch subscribed users: %w", err)
+	}
+	if len(users) == 0 {
+		return nil
+	}
+
+	conferences, err := s.conferenceService.GetConferences(conference.ConferenceFilter{
+		Visibilities: []string{conference.VisibilityPublished},
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, c := range conferences {
+		// skip conferences with nothing about to start without looking up
+		// everyone's favourites
+		upcoming, err := s.conferenceService.GetEvents(c.ID, conference.EventFilter{
+			StartingWithin: s.reminderLead,
+		}, "", 1)
+		if err != nil || len(upcoming.Events) == 0 {
+			continue
+		}
+
+		for _, userID := range users {
+			events, err := s.favouritesService.GetFavouriteEventsForUserConference(userID, c.ID)
+			if err != nil {
+				// the user may no longer be able to access the conference
+				if !errors.Is(err, conference.ErrConferenceNotFound) {
+					errs = append(errs, err)
+				}
+				continue
+			}
+
+			for _, event := range events {
+				if err := s.remind(userID, event); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *service) remind(userID int32, event favourites.FavouriteEvent) error {
+	now := time.Now()
+	if event.Event.Start.Before(now) || event.Event.Start.After(now.Add(s.reminderLead)) {
+		return nil
+	}
+
+	queries := sqlc.New(s.pool)
+
+	created, err := queries.CreatePushReminder(context.Background(), sqlc.CreatePushReminderParams{
+		FavouriteID: event.Favourite.ID,
+		EventStart: pgtype.Timestamptz{
+			Time:  event.Event.Start,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("could not record reminder: %w", err)
+	}
+	if created == 0 {
+		return nil
+	}
+
+	minutes := int(event.Event.Start.Sub(now).Round(time.Minute).Minutes())
+	body := fmt.Sprintf("Starts in %d minutes in %s", minutes, event.Event.Room)
+	if minutes <= 1 {
+		body = "Starting now in " + event.Event.Room
+	}
+
+	err = s.SendToUser(userID, Message{
+		Title: event.Event.Title,
+		Body:  body,
+		URL:   "/live",
+		Tag:   fmt.Sprintf("reminder-%d", event.Favourite.ID),
+	})
+	if errors.Is(err, ErrSubscriptionNotFound) {
+		return nil
+	}
+	return err
+}
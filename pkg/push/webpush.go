@@ -0,0 +1,246 @@
+package push
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// the record size advertised in the content coding header; payloads are
+// always small enough to fit in a single record
+const recordSize = 4096
+
+// how long the push service should hold on to a message for a device which
+// is offline; a reminder is useless once the event has started
+const messageTTL = 10 * time.Minute
+
+var errSubscriptionGone = errors.New("push subscription has expired")
+
+// vapidKeys identify this server to push services (RFC 8292).
+type vapidKeys struct {
+	private *ecdsa.PrivateKey
+	// the uncompressed public key, base64url encoded, which browsers need
+	// as the applicationServerKey when subscribing
+	public string
+}
+
+func generateVapidKeys() (*vapidKeys, string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, "", err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	keys, err := newVapidKeys(key)
+	if err != nil {
+		return nil, "", err
+	}
+	return keys, base64.StdEncoding.EncodeToString(der), nil
+}
+
+func parseVapidKeys(encoded string) (*vapidKeys, error) {
+	der, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	ecdsaKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok || ecdsaKey.Curve != elliptic.P256() {
+		return nil, errors.New("VAPID key is not a P-256 key")
+	}
+	return newVapidKeys(ecdsaKey)
+}
+
+func newVapidKeys(key *ecdsa.PrivateKey) (*vapidKeys, error) {
+	public, err := key.PublicKey.ECDH()
+	if err != nil {
+		return nil, err
+	}
+
+	return &vapidKeys{
+		private: key,
+		public:  base64.RawURLEncoding.EncodeToString(public.Bytes()),
+	}, nil
+}
+
+// authorization returns the VAPID Authorization header for a request to
+// the push service hosting endpoint.
+func (k *vapidKeys) authorization(endpoint *url.URL, subject string) (string, error) {
+	header, _ := json.Marshal(map[string]string{"typ": "JWT", "alg": "ES256"})
+	claims, _ := json.Marshal(map[string]any{
+		"aud": endpoint.Scheme + "://" + endpoint.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": subject,
+	})
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, k.private, hash[:])
+	if err != nil {
+		return "", err
+	}
+
+	// JWS wants the raw, fixed length r and s rather than ASN.1
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	token := unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+	return "vapid t=" + token + ", k=" + k.public, nil
+}
+
+// encrypt encodes a message for a subscription using the aes128gcm content
+// coding (RFC 8188) with keys derived as described in RFC 8291.
+func encrypt(plaintext []byte, p256dh string, auth string) ([]byte, error) {
+	userAgentKey, err := decodeKey(p256dh)
+	if err != nil {
+		return nil, fmt.Errorf("bad p256dh key: %w", err)
+	}
+	userAgentPublic, err := ecdh.P256().NewPublicKey(userAgentKey)
+	if err != nil {
+		return nil, fmt.Errorf("bad p256dh key: %w", err)
+	}
+	authSecret, err := decodeKey(auth)
+	if err != nil {
+		return nil, fmt.Errorf("bad auth secret: %w", err)
+	}
+
+	serverPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serverPublic := serverPrivate.PublicKey().Bytes()
+
+	sharedSecret, err := serverPrivate.ECDH(userAgentPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	keyInfo := append([]byte("WebPush: info\x00"), userAgentKey...)
+	keyInfo = append(keyInfo, serverPublic...)
+	ikm, err := derive(authSecret, sharedSecret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	contentKey, err := derive(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := derive(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(contentKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// a single record, marked as the last with a 0x02 delimiter
+	record := append(bytes.Clone(plaintext), 0x02)
+	if len(record)+gcm.Overhead() > recordSize {
+		return nil, errors.New("push message is too large")
+	}
+
+	var body bytes.Buffer
+	body.Write(salt)
+	binary.Write(&body, binary.BigEndian, uint32(recordSize))
+	body.WriteByte(byte(len(serverPublic)))
+	body.Write(serverPublic)
+	body.Write(gcm.Seal(nil, nonce, record, nil))
+
+	return body.Bytes(), nil
+}
+
+func derive(salt []byte, secret []byte, info []byte, length int) ([]byte, error) {
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// decodeKey accepts keys as browsers give them (base64url without padding)
+// as well as with padding or in standard base64.
+func decodeKey(key string) ([]byte, error) {
+	for _, encoding := range []*base64.Encoding{base64.RawURLEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.StdEncoding} {
+		if decoded, err := encoding.DecodeString(key); err == nil {
+			return decoded, nil
+		}
+	}
+	return nil, errors.New("not valid base64")
+}
+
+// send delivers an encrypted message to a push service.
+func send(client *http.Client, keys *vapidKeys, subject string, endpoint string, body []byte) error {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+
+	authorization, err := keys.authorization(endpointURL, subject)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", authorization)
+	request.Header.Set("Content-Encoding", "aes128gcm")
+	request.Header.Set("Content-Type", "application/octet-stream")
+	request.Header.Set("TTL", strconv.Itoa(int(messageTTL.Seconds())))
+	request.Header.Set("Urgency", "high")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+
+	switch {
+	case response.StatusCode == http.StatusNotFound || response.StatusCode == http.StatusGone:
+		return errSubscriptionGone
+	case response.StatusCode >= 300:
+		return fmt.Errorf("push service responded with %s", response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package recommendation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/LMBishop/confplanner/pkg/favourites"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Service interface {
+	GetRecommendationsForUser(id int32, conferenceID int32, limit int) ([]Recommendation, error)
+}
+
+// Recommendation is an event the user has not favourited which they may be
+// interested in, along with the reasons it was suggested.
+type Recommendation struct {
+	Event   conference.Event
+	Score   float64
+	Reasons []string
+}
+
+const (
+	ReasonTrack        = "track"
+	ReasonType         = "type"
+	ReasonSpeaker      = "speaker"
+	ReasonSimilar      = "similar"
+	ReasonCoFavourited = "co-favourited"
+)
+
+// weights given to each signal when scoring an event
+const (
+	trackWeight       = 0.2
+	typeWeight        = 0.1
+	speakerWeight     = 0.2
+	similarityWeight  = 0.3
+	coFavouriteWeight = 0.2
+)
+
+const (
+	// how similar an event's text must be to a favourite before it is
+	// given as a reason for the recommendation
+	similarityThreshold = 0.15
+	// events scoring below this are not worth suggesting
+	minimumScore = 0.05
+)
+
+type service struct {
+	pool              *pgxpool.Pool
+	favouritesService favourites.Service
+	conferenceService conference.Service
+	conflictBuffer    time.Duration
+}
+
+func NewService(pool *pgxpool.Pool, favouritesService favourites.Service, conferenceService conference.Service, conflictBuffer time.Duration) Service {
+	return &service{
+		pool:              pool,
+		favouritesService: favouritesService,
+		conferenceService: conferenceService,
+		conflictBuffer:    conflictBuffer,
+	}
+}
+
+func (s *service) GetRecommendationsForUser(id int32, conferenceID int32, limit int) ([]Recommendation, error) {
+	schedule, _, err := s.conferenceService.GetSchedule(conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	favouriteEvents, err := s.favouritesService.GetFavouriteEventsForUserConference(id, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(favouriteEvents) == 0 {
+		return []Recommendation{}, nil
+	}
+
+	queries := sqlc.New(s.pool)
+
+	coFavourites, err := queries.GetCoFavouriteCounts(context.Background(), sqlc.GetCoFavouriteCountsParams{
+		ConferenceID: conferenceID,
+		UserID:       id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not count co-favourites: %w", err)
+	}
+
+	var events []conference.Event
+	for _, day := range schedule.Days {
+		for _, room := range day.Rooms {
+			events = append(events, room.Events...)
+		}
+	}
+
+	favourited := make(map[string]bool)
+	tracks := make(map[string]int)
+	types := make(map[string]int)
+	speakers := make(map[string]bool)
+	for _, favouriteEvent := range favouriteEvents {
+		event := favouriteEvent.Event
+		favourited[event.Key()] = true
+		if event.Track != "" {
+			tracks[event.Track]++
+		}
+		if event.Type != "" {
+			types[event.Type]++
+		}
+		for _, person := range event.Persons {
+			speakers[person.Name] = true
+		}
+	}
+
+	coFavouriteCounts := make(map[string]int64)
+	var maxCoFavourites int64
+	for _, row := range coFavourites {
+		var key string
+		if row.EventGuid.Valid {
+			key = row.EventGuid.String()
+		} else {
+			key = fmt.Sprint(row.EventID.Int32)
+		}
+		coFavouriteCounts[key] += row.Users
+		if coFavouriteCounts[key] > maxCoFavourites {
+			maxCoFavourites = coFavouriteCounts[key]
+		}
+	}
+
+	index := newTfidfIndex(events)
+	total := float64(len(favouriteEvents))
+
+	recommendations := make([]Recommendation, 0)
+	for i, event := range events {
+		if favourited[event.Key()] || s.clashes(event, favouriteEvents) {
+			continue
+		}
+
+		var score float64
+		var reasons []string
+
+		if n := tracks[event.Track]; n > 0 {
+			score += trackWeight * float64(n) / total
+			reasons = append(reasons, ReasonTrack)
+		}
+		if n := types[event.Type]; n > 0 {
+			score += typeWeight * float64(n) / total
+			reasons = append(reasons, ReasonType)
+		}
+		for _, person := range event.Persons {
+			if speakers[person.Name] {
+				score += speakerWeight
+				reasons = append(reasons, ReasonSpeaker)
+				break
+			}
+		}
+
+		var similarity float64
+		for _, favouriteEvent := range favouriteEvents {
+			if j, ok := index.positions[favouriteEvent.Event.Key()]; ok {
+				similarity = max(similarity, index.similarity(i, j))
+			}
+		}
+		score += similarityWeight * similarity
+		if similarity >= similarityThreshold {
+			reasons = append(reasons, ReasonSimilar)
+		}
+
+		count := coFavouriteCounts[event.GUID]
+		if count == 0 {
+			count = coFavouriteCounts[fmt.Sprint(event.ID)]
+		}
+		if count > 0 {
+			score += coFavouriteWeight * float64(count) / float64(maxCoFavourites)
+			reasons = append(reasons, ReasonCoFavourited)
+		}
+
+		if score < minimumScore {
+			continue
+		}
+
+		recommendations = append(recommendations, Recommendation{
+			Event:   event,
+			Score:   score,
+			Reasons: reasons,
+		})
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Score > recommendations[j].Score
+	})
+	if limit > 0 && len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+
+	return recommendations, nil
+}
+
+func (s *service) clashes(event conference.Event, favouriteEvents []favourites.FavouriteEvent) bool {
+	for _, favouriteEvent := range favouriteEvents {
+		if favourites.Overlaps(event, favouriteEvent.Event, s.conflictBuffer) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package recommendation
+
+import (
+	"html"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/microcosm-cc/bluemonday"
+)
+
+var stopWords = map[string]bool{
+	"and": true, "are": true, "but": true, "can": true, "for": true,
+	"from": true, "has": true, "have": true, "how": true, "into": true,
+	"its": true, "not": true, "our": true, "that": true, "the": true,
+	"their": true, "them": true, "then": true, "there": true, "these": true,
+	"this": true, "was": true, "were": true, "what": true, "when": true,
+	"which": true, "will": true, "with": true, "you": true, "your": true,
+	"about": true, "also": true, "all": true, "more": true, "some": true,
+	"such": true, "talk": true, "they": true, "been": true, "who": true,
+}
+
+// tfidfIndex holds a normalised TF-IDF vector for the title and abstract of
+// every event in a schedule.
+type tfidfIndex struct {
+	vectors   []map[string]float64
+	positions map[string]int
+}
+
+func newTfidfIndex(events []conference.Event) *tfidfIndex {
+	policy := bluemonday.StrictPolicy()
+
+	documents := make([][]string, len(events))
+	frequencies := make(map[string]int)
+	positions := make(map[string]int, len(events))
+	for i, event := range events {
+		text := event.Title + " " + html.UnescapeString(policy.Sanitize(event.Abstract))
+		documents[i] = terms(text)
+		positions[event.Key()] = i
+
+		seen := make(map[string]bool)
+		for _, term := range documents[i] {
+			if !seen[term] {
+				seen[term] = true
+				frequencies[term]++
+			}
+		}
+	}
+
+	n := float64(len(events))
+	vectors := make([]map[string]float64, len(events))
+	for i, document := range documents {
+		vector := make(map[string]float64)
+		for _, term := range document {
+			vector[term]++
+		}
+
+		var norm float64
+		for term, count := range vector {
+			idf := math.Log((1+n)/(1+float64(frequencies[term]))) + 1
+			weight := count / float64(len(document)) * idf
+			vector[term] = weight
+			norm += weight * weight
+		}
+		norm = math.Sqrt(norm)
+		if norm > 0 {
+			for term := range vector {
+				vector[term] /= norm
+			}
+		}
+
+		vectors[i] = vector
+	}
+
+	return &tfidfIndex{
+		vectors:   vectors,
+		positions: positions,
+	}
+}
+
+// similarity returns the cosine similarity between the events at positions
+// i and j.
+func (t *tfidfIndex) similarity(i int, j int) float64 {
+	a, b := t.vectors[i], t.vectors[j]
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+
+	var dot float64
+	for term, weight := range a {
+		dot += weight * b[term]
+	}
+	return dot
+}
+
+func terms(s string) []string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if len([]rune(word)) < 3 || stopWords[word] {
+			continue
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
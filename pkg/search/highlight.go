@@ -0,0 +1,73 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// how many characters of context to show either side of the first match in
+// long fields such as the abstract
+const snippetContext = 80
+
+// highlight returns text with every word in terms wrapped in <mark> tags.
+// The rest of the text is HTML escaped, so the result is safe to render.
+func highlight(text string, terms map[string]bool, snippet bool) string {
+	type span struct {
+		start, end int
+	}
+
+	var spans []span
+	start := -1
+	for i, r := range text + " " {
+		if isSeparator(r) {
+			if start >= 0 && terms[strings.ToLower(text[start:i])] {
+				spans = append(spans, span{start, i})
+			}
+			start = -1
+		} else if start < 0 {
+			start = i
+		}
+	}
+
+	from, to := 0, len(text)
+	if snippet && len(spans) > 0 {
+		from = max(0, spans[0].start-snippetContext)
+		to = min(len(text), spans[0].end+snippetContext)
+		// avoid cutting words in half
+		if i := strings.IndexByte(text[from:spans[0].start], ' '); from > 0 && i >= 0 {
+			from += i + 1
+		}
+		if i := strings.LastIndexByte(text[spans[0].end:to], ' '); to < len(text) && i >= 0 {
+			to = spans[0].end + i
+		}
+		for from > 0 && !utf8.RuneStart(text[from]) {
+			from--
+		}
+		for to < len(text) && !utf8.RuneStart(text[to]) {
+			to++
+		}
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	position := from
+	for _, s := range spans {
+		if s.start < from || s.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[position:s.start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[s.start:s.end]))
+		b.WriteString("</mark>")
+		position = s.end
+	}
+	b.WriteString(html.EscapeString(text[position:to]))
+	if to < len(text) {
+		b.WriteString("…")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,197 @@
+package search
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// fields of an event which are searched, and how much a match in each
+// counts towards an event's score
+const (
+	FieldTitle    = "title"
+	FieldAbstract = "abstract"
+	FieldSpeakers = "speakers"
+	FieldTrack    = "track"
+	FieldRoom     = "room"
+)
+
+var fieldWeights = map[string]float64{
+	FieldTitle:    3,
+	FieldSpeakers: 2.5,
+	FieldTrack:    2,
+	FieldRoom:     1.5,
+	FieldAbstract: 1,
+}
+
+type posting struct {
+	document  int
+	field     string
+	frequency int
+}
+
+type document struct {
+	event  conference.Event
+	fields map[string]string
+}
+
+// index is an inverted index over the events of a single schedule. It is
+// immutable once built, so can be read concurrently.
+type index struct {
+	// the generation of the schedule the index was built from
+	generation uint64
+	documents  []document
+	postings   map[string][]posting
+	terms      []string
+}
+
+func newIndex(schedule *conference.Schedule) *index {
+	policy := bluemonday.StrictPolicy()
+
+	idx := &index{
+		generation: schedule.Generation(),
+		postings:   make(map[string][]posting),
+	}
+	for _, day := range schedule.Days {
+		for _, room := range day.Rooms {
+			for _, event := range room.Events {
+				speakers := make([]string, 0, len(event.Persons))
+				for _, person := range event.Persons {
+					speakers = append(speakers, person.Name)
+				}
+
+				doc := document{
+					event: event,
+					fields: map[string]string{
+						FieldTitle:    event.Title,
+						FieldAbstract: html.UnescapeString(policy.Sanitize(event.Abstract)),
+						FieldSpeakers: strings.Join(speakers, ", "),
+						FieldTrack:    event.Track,
+						FieldRoom:     event.Room,
+					},
+				}
+				idx.add(doc)
+			}
+		}
+	}
+
+	idx.terms = make([]string, 0, len(idx.postings))
+	for term := range idx.postings {
+		idx.terms = append(idx.terms, term)
+	}
+	sort.Strings(idx.terms)
+
+	return idx
+}
+
+func (idx *index) add(doc document) {
+	i := len(idx.documents)
+	idx.documents = append(idx.documents, doc)
+
+	for field, text := range doc.fields {
+		frequencies := make(map[string]int)
+		for _, term := range tokenise(text) {
+			frequencies[term]++
+		}
+		for term, frequency := range frequencies {
+			idx.postings[term] = append(idx.postings[term], posting{
+				document:  i,
+				field:     field,
+				frequency: frequency,
+			})
+		}
+	}
+}
+
+// expand returns the indexed terms matching a query term. The final term of
+// a query is treated as a prefix so that results can be shown as the user
+// types.
+func (idx *index) expand(term string, prefix bool) []string {
+	if !prefix {
+		if _, ok := idx.postings[term]; ok {
+			return []string{term}
+		}
+		return nil
+	}
+
+	var terms []string
+	for i := sort.SearchStrings(idx.terms, term); i < len(idx.terms) && strings.HasPrefix(idx.terms[i], term); i++ {
+		terms = append(terms, idx.terms[i])
+	}
+	return terms
+}
+
+type match struct {
+	score  float64
+	fields map[string]bool
+	terms  map[string]bool
+}
+
+// search returns matches for documents containing every query term.
+func (idx *index) search(query []string) map[int]*match {
+	n := float64(len(idx.documents))
+
+	var matches map[int]*match
+	for i, queryTerm := range query {
+		termMatches := make(map[int]*match)
+		for _, term := range idx.expand(queryTerm, i == len(query)-1) {
+			postings := idx.postings[term]
+
+			documents := make(map[int]bool)
+			for _, p := range postings {
+				documents[p.document] = true
+			}
+			df := float64(len(documents))
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+			for _, p := range postings {
+				m, ok := termMatches[p.document]
+				if !ok {
+					m = &match{
+						fields: make(map[string]bool),
+						terms:  make(map[string]bool),
+					}
+					termMatches[p.document] = m
+				}
+				tf := float64(p.frequency)
+				m.score += fieldWeights[p.field] * tf / (tf + 1.2) * idf
+				m.fields[p.field] = true
+				m.terms[term] = true
+			}
+		}
+
+		if matches == nil {
+			matches = termMatches
+			continue
+		}
+		for doc, m := range matches {
+			termMatch, ok := termMatches[doc]
+			if !ok {
+				delete(matches, doc)
+				continue
+			}
+			m.score += termMatch.score
+			for field := range termMatch.fields {
+				m.fields[field] = true
+			}
+			for term := range termMatch.terms {
+				m.terms[term] = true
+			}
+		}
+	}
+
+	return matches
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+func tokenise(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), isSeparator)
+}
@@ -0,0 +1,114 @@
+package search
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/LMBishop/confplanner/pkg/conference"
+)
+
+type Service interface {
+	Search(conferenceID int32, query string, limit int) ([]Result, error)
+	IndexSchedule(update conference.ScheduleUpdate)
+}
+
+// Result is an event matching a search query. Highlights holds the matching
+// fields with the matched words wrapped in <mark> tags.
+type Result struct {
+	Event      conference.Event
+	Score      float64
+	Highlights map[string]string
+}
+
+type service struct {
+	conferenceService conference.Service
+	indexes           map[int32]*index
+	lock              sync.RWMutex
+}
+
+func NewService(conferenceService conference.Service) Service {
+	return &service{
+		conferenceService: conferenceService,
+		indexes:           make(map[int32]*index),
+	}
+}
+
+// IndexSchedule rebuilds the search index for a conference, or drops it
+// once the conference has been deleted. It is intended to be registered as
+// a schedule listener so the index is kept up to date whenever the schedule
+// is refreshed.
+func (s *service) IndexSchedule(update conference.ScheduleUpdate) {
+	if update.Deleted {
+		s.lock.Lock()
+		delete(s.indexes, update.ConferenceID)
+		s.lock.Unlock()
+		return
+	}
+
+	s.swapIndex(update.ConferenceID, newIndex(update.Schedule))
+}
+
+// swapIndex replaces a conference's index, unless it was built from an
+// older schedule than the current one. Indexes are built both by the
+// listener and when searching before it has run, so may arrive out of
+// order.
+func (s *service) swapIndex(conferenceID int32, idx *index) *index {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if current, ok := s.indexes[conferenceID]; ok && current.generation > idx.generation {
+		return current
+	}
+	s.indexes[conferenceID] = idx
+	return idx
+}
+
+func (s *service) Search(conferenceID int32, query string, limit int) ([]Result, error) {
+	schedule, _, err := s.conferenceService.GetSchedule(conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.RLock()
+	idx, ok := s.indexes[conferenceID]
+	s.lock.RUnlock()
+	if !ok || idx.generation < schedule.Generation() {
+		// the listener may not have run yet
+		idx = s.swapIndex(conferenceID, newIndex(schedule))
+	}
+
+	terms := tokenise(query)
+	if len(terms) == 0 {
+		return []Result{}, nil
+	}
+
+	matches := idx.search(terms)
+
+	results := make([]Result, 0, len(matches))
+	for i, m := range matches {
+		doc := idx.documents[i]
+
+		highlights := make(map[string]string)
+		for field := range m.fields {
+			highlights[field] = highlight(doc.fields[field], m.terms, field == FieldAbstract)
+		}
+
+		results = append(results, Result{
+			Event:      doc.event,
+			Score:      m.score,
+			Highlights: highlights,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Event.Start.Before(results[j].Event.Start)
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
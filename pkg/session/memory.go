@@ -40,6 +40,19 @@ func (s *memoryStore) GetBySID(sid uint) *UserSession {
 	return s.sessionsBySID[sid]
 }
 
+func (s *memoryStore) GetByUserID(uid int32) []*UserSession {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	sessions := make([]*UserSession, 0)
+	for _, session := range s.sessionsBySID {
+		if session.UserID == uid {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
+
 func (s *memoryStore) Create(uid int32, username string, ip string, ua string, admin bool) (*UserSession, error) {
 	token := generateSessionToken()
 
@@ -87,6 +100,19 @@ func (s *memoryStore) Destroy(sid uint) error {
 	return nil
 }
 
+func (s *memoryStore) DestroyByUserID(uid int32) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for sid, session := range s.sessionsBySID {
+		if session.UserID == uid {
+			delete(s.sessionsBySID, sid)
+			delete(s.sessionsByToken, session.Token)
+		}
+	}
+	return nil
+}
+
 func generateSessionToken() string {
 	b := make([]byte, 100)
 	if _, err := rand.Read(b); err != nil {
@@ -5,8 +5,10 @@ import "time"
 type Service interface {
 	GetByToken(token string) *UserSession
 	GetBySID(sid uint) *UserSession
+	GetByUserID(uid int32) []*UserSession
 	Create(uid int32, username string, ip string, ua string, admin bool) (*UserSession, error)
 	Destroy(sid uint) error
+	DestroyByUserID(uid int32) error
 }
 
 type UserSession struct {
@@ -0,0 +1,201 @@
+package speaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/LMBishop/confplanner/pkg/notification"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Service interface {
+	FollowSpeaker(id int32, conferenceID int32, personID int) (*sqlc.SpeakerFollow, error)
+	UnfollowSpeaker(id int32, conferenceID int32, personID int) error
+	GetFollowsForUser(id int32) ([]sqlc.SpeakerFollow, error)
+	NotifyScheduleChanges(update conference.ScheduleUpdate) error
+}
+
+var (
+	ErrFollowNotFound = errors.New("follow not found")
+	// speakers the schedule gives no ID to all share person ID 0, so
+	// following one would follow them all
+	ErrSpeakerHasNoID = errors.New("speaker has no ID in the schedule and cannot be followed")
+)
+
+type service struct {
+	pool                *pgxpool.Pool
+	conferenceService   conference.Service
+	notificationService notification.Service
+}
+
+func NewService(pool *pgxpool.Pool, conferenceService conference.Service, notificationService notification.Service) Service {
+	return &service{
+		pool:                pool,
+		conferenceService:   conferenceService,
+		notificationService: notificationService,
+	}
+}
+
+func (s *service) FollowSpeaker(id int32, conferenceID int32, personID int) (*sqlc.SpeakerFollow, error) {
+	if personID == 0 {
+		return nil, ErrSpeakerHasNoID
+	}
+
+	// conferences the user can't access are reported as not found
+	ok, err := s.conferenceService.CanAccess(conferenceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, conference.ErrConferenceNotFound
+	}
+
+	speaker, err := s.conferenceService.GetSpeaker(conferenceID, personID)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := sqlc.New(s.pool)
+
+	follow, err := queries.CreateSpeakerFollow(context.Background(), sqlc.CreateSpeakerFollowParams{
+		UserID:       id,
+		ConferenceID: conferenceID,
+		PersonID:     int32(personID),
+		PersonName:   speaker.Person.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create follow: %w", err)
+	}
+
+	return &follow, nil
+}
+
+func (s *service) UnfollowSpeaker(id int32, conferenceID int32, personID int) error {
+	queries := sqlc.New(s.pool)
+
+	rowsAffected, err := queries.DeleteSpeakerFollow(context.Background(), sqlc.DeleteSpeakerFollowParams{
+		UserID:       id,
+		ConferenceID: conferenceID,
+		PersonID:     int32(personID),
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete follow: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrFollowNotFound
+	}
+
+	return nil
+}
+
+func (s *service) GetFollowsForUser(id int32) ([]sqlc.SpeakerFollow, error) {
+	queries := sqlc.New(s.pool)
+
+	follows, err := queries.GetSpeakerFollowsForUser(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch follows: %w", err)
+	}
+
+	return follows, nil
+}
+
+// NotifyScheduleChanges notifies users following a speaker whose talks
+// were added, changed or removed by a schedule update.
+func (s *service) NotifyScheduleChanges(update conference.ScheduleUpdate) error {
+	if update.Changes == nil || update.Changes.Empty() {
+		return nil
+	}
+	conferenceID := update.ConferenceID
+
+	queries := sqlc.New(s.pool)
+
+	follows, err := queries.GetSpeakerFollowsForConference(context.Background(), conferenceID)
+	if err != nil {
+		return fmt.Errorf("could not fetch follows: %w", err)
+	}
+	if len(follows) == 0 {
+		return nil
+	}
+
+	followers := make(map[int][]int32)
+	for _, follow := range follows {
+		followers[int(follow.PersonID)] = append(followers[int(follow.PersonID)], follow.UserID)
+	}
+
+	for _, event := range update.Changes.Added {
+		for userID, name := range followersOf(event, followers) {
+			if err := s.notify(conferenceID, userID, notification.KindSpeakerEventAdded, "New talk by "+name, event); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, change := range update.Changes.Changed {
+		previousFollowers := followersOf(change.Before, followers)
+		for userID, name := range followersOf(change.After, followers) {
+			_, wasFollowing := previousFollowers[userID]
+			switch {
+			case !wasFollowing:
+				err = s.notify(conferenceID, userID, notification.KindSpeakerEventAdded, "New talk by "+name, change.After)
+			case hasChanged(change.Before, change.After):
+				err = s.notify(conferenceID, userID, notification.KindSpeakerEventChanged, "Talk by "+name+" has changed", change.After)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, event := range update.Changes.Removed {
+		for userID, name := range followersOf(event, followers) {
+			if err := s.notify(conferenceID, userID, notification.KindSpeakerEventCancelled, "Talk by "+name+" has been cancelled", event); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *service) notify(conferenceID int32, userID int32, kind string, title string, event conference.Event) error {
+	// not every source gives events a valid UUID, in which case the
+	// notification is sent without one
+	var guid pgtype.UUID
+	_ = guid.Scan(event.GUID)
+
+	return s.notificationService.Notify([]int32{userID}, notification.Notification{
+		ConferenceID: conferenceID,
+		Kind:         kind,
+		Title:        title,
+		Body:         event.Title + ", " + event.Start.Format("Mon 02 Jan 15:04") + " in " + event.Room,
+		EventGUID:    guid,
+	})
+}
+
+// followersOf returns the users following any of the event's speakers,
+// along with the name of the (first) speaker they follow.
+func followersOf(event conference.Event, followers map[int][]int32) map[int32]string {
+	users := make(map[int32]string)
+	for _, person := range event.Persons {
+		if person.ID == 0 {
+			continue
+		}
+		for _, userID := range followers[person.ID] {
+			if _, ok := users[userID]; !ok {
+				users[userID] = person.Name
+			}
+		}
+	}
+	return users
+}
+
+func hasChanged(before conference.Event, after conference.Event) bool {
+	return before.Title != after.Title ||
+		!before.Start.Equal(after.Start) ||
+		!before.End.Equal(after.End) ||
+		before.Room != after.Room
+}
@@ -0,0 +1,140 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ProfileUpdate describes a partial update to a user's profile. Nil fields
+// are left unchanged, and empty values clear the preference.
+type ProfileUpdate struct {
+	DisplayName          *string
+	TimeZone             *string
+	Clock                *string
+	Language             *string
+	DefaultConferenceID  *int32
+	FavouritesVisibility *string
+	EmailDigest          *bool
+	EmailAlerts          *bool
+}
+
+func (s *service) GetProfile(id int32) (*sqlc.UserProfile, error) {
+	queries := sqlc.New(s.pool)
+
+	profile, err := queries.GetUserProfile(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &sqlc.UserProfile{
+				UserID:               id,
+				Clock:                "24h",
+				FavouritesVisibility: "groups",
+				EmailDigest:          true,
+				EmailAlerts:          true,
+			}, nil
+		}
+		return nil, fmt.Errorf("could not fetch user profile: %w", err)
+	}
+
+	return &profile, nil
+}
+
+func (s *service) UpdateProfile(id int32, update ProfileUpdate) (*sqlc.UserProfile, error) {
+	profile, err := s.GetProfile(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.DisplayName != nil {
+		profile.DisplayName = optionalText(*update.DisplayName)
+	}
+	if update.TimeZone != nil {
+		if *update.TimeZone != "" {
+			if _, err := time.LoadLocation(*update.TimeZone); err != nil {
+				return nil, ErrInvalidTimeZone
+			}
+		}
+		profile.TimeZone = optionalText(*update.TimeZone)
+	}
+	if update.Clock != nil {
+		profile.Clock = *update.Clock
+	}
+	if update.Language != nil {
+		profile.Language = optionalText(*update.Language)
+	}
+	if update.DefaultConferenceID != nil {
+		profile.DefaultConferenceID = pgtype.Int4{
+			Int32: *update.DefaultConferenceID,
+			Valid: *update.DefaultConferenceID != 0,
+		}
+	}
+
+	if update.FavouritesVisibility != nil {
+		profile.FavouritesVisibility = *update.FavouritesVisibility
+	}
+	if update.EmailDigest != nil {
+		profile.EmailDigest = *update.EmailDigest
+	}
+	if update.EmailAlerts != nil {
+		profile.EmailAlerts = *update.EmailAlerts
+	}
+
+	queries := sqlc.New(s.pool)
+
+	updatedProfile, err := queries.UpsertUserProfile(context.Background(), sqlc.UpsertUserProfileParams{
+		UserID:               id,
+		DisplayName:          profile.DisplayName,
+		TimeZone:             profile.TimeZone,
+		Clock:                profile.Clock,
+		Language:             profile.Language,
+		DefaultConferenceID:  profile.DefaultConferenceID,
+		FavouritesVisibility: profile.FavouritesVisibility,
+		EmailDigest:          profile.EmailDigest,
+		EmailAlerts:          profile.EmailAlerts,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, ErrConferenceNotFound
+		}
+		return nil, fmt.Errorf("could not update user profile: %w", err)
+	}
+
+	return &updatedProfile, nil
+}
+
+// Location returns the time zone the user would like times rendered in,
+// falling back to UTC if they have not set one.
+func Location(profile *sqlc.UserProfile) *time.Location {
+	if profile == nil || !profile.TimeZone.Valid {
+		return time.UTC
+	}
+
+	location, err := time.LoadLocation(profile.TimeZone.String)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// TimeLayout returns a layout for rendering a time of day according to the
+// user's preferred clock.
+func TimeLayout(profile *sqlc.UserProfile) string {
+	if profile != nil && profile.Clock == "12h" {
+		return "3:04 PM"
+	}
+	return "15:04"
+}
+
+func optionalText(s string) pgtype.Text {
+	return pgtype.Text{
+		String: s,
+		Valid:  s != "",
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/LMBishop/confplanner/pkg/database/sqlc"
 	"github.com/jackc/pgx/v5"
@@ -18,12 +19,20 @@ type Service interface {
 	CreateUser(username string, password string) (*sqlc.User, error)
 	GetUserByName(username string) (*sqlc.User, error)
 	GetUserByID(id int32) (*sqlc.User, error)
+	DeleteUser(id int32, password string, loginTime time.Time) error
+	GetProfile(id int32) (*sqlc.UserProfile, error)
+	UpdateProfile(id int32, update ProfileUpdate) (*sqlc.UserProfile, error)
+	SaveOIDCClaims(id int32, claims string) error
 }
 
 var (
 	ErrUserExists                = errors.New("user already exists")
 	ErrUserNotFound              = errors.New("user not found")
 	ErrNotAcceptingRegistrations = errors.New("not currently accepting registrations")
+	ErrIncorrectPassword         = errors.New("incorrect password")
+	ErrReauthenticationRequired  = errors.New("sign in again to continue")
+	ErrInvalidTimeZone           = errors.New("invalid time zone")
+	ErrConferenceNotFound        = errors.New("conference not found")
 )
 
 type service struct {
@@ -106,3 +115,72 @@ func (s *service) GetUserByID(id int32) (*sqlc.User, error) {
 
 	return &user, nil
 }
+
+// freshLoginWindow is how recently users without a password must have
+// signed in to delete their account, in place of re-entering a password.
+const freshLoginWindow = 10 * time.Minute
+
+// DeleteUser deletes a user once they have re-authenticated, either with
+// their password or, for users created through an identity provider, by
+// having signed in within freshLoginWindow of loginTime.
+func (s *service) DeleteUser(id int32, password string, loginTime time.Time) error {
+	queries := sqlc.New(s.pool)
+
+	user, err := queries.GetUserByID(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("could not fetch user: %w", err)
+	}
+
+	if user.Password.Valid {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password.String), []byte(password)); err != nil {
+			if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+				return ErrIncorrectPassword
+			}
+			return err
+		}
+	} else if time.Since(loginTime) > freshLoginWindow {
+		return ErrReauthenticationRequired
+	}
+
+	ctx := context.Background()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	queries = queries.WithTx(tx)
+
+	// shared events outlive their author, personal events don't
+	if err := queries.DeletePersonalCustomEventsForUser(ctx, pgtype.Int4{Int32: id, Valid: true}); err != nil {
+		return fmt.Errorf("could not delete personal events: %w", err)
+	}
+	if err := queries.DeleteUser(ctx, id); err != nil {
+		return fmt.Errorf("could not delete user: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SaveOIDCClaims keeps the claims from a user's latest OIDC login, which
+// conferences may restrict access by.
+func (s *service) SaveOIDCClaims(id int32, claims string) error {
+	queries := sqlc.New(s.pool)
+
+	if err := queries.UpsertUserOIDCClaims(context.Background(), sqlc.UpsertUserOIDCClaimsParams{
+		UserID: id,
+		Claims: []byte(claims),
+	}); err != nil {
+		return fmt.Errorf("could not save claims: %w", err)
+	}
+
+	return nil
+}
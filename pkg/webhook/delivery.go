@@ -0,0 +1,250 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// retryDelays is how long to wait before each retry of a failed delivery.
+// A delivery which still fails after the last is given up on.
+var retryDelays = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	6 * time.Hour,
+}
+
+const (
+	// how many deliveries are claimed by the worker at once
+	deliveryBatchSize = 20
+	// how much of a conference webhook endpoint's response is kept in the
+	// delivery log. Responses to user webhooks aren't kept, as any user can
+	// create one.
+	responseBodyLimit = 2048
+)
+
+func (s *service) runDeliveries() {
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.wake:
+		}
+
+		if err := s.deliverDue(); err != nil {
+			slog.Error("failed to deliver webhooks", "error", err)
+		}
+	}
+}
+
+// deliverDue sends every delivery whose next attempt is due. Claiming a
+// delivery pushes its next attempt back, so another instance won't send it
+// at the same time, and one interrupted mid-send is retried later.
+func (s *service) deliverDue() error {
+	queries := sqlc.New(s.pool)
+
+	for {
+		deliveries, err := queries.ClaimDueWebhookDeliveries(context.Background(), deliveryBatchSize)
+		if err != nil {
+			return fmt.Errorf("could not claim deliveries: %w", err)
+		}
+
+		webhooks := make(map[int32]*sqlc.Webhook)
+		var errs []error
+		for _, delivery := range deliveries {
+			webhook, ok := webhooks[delivery.WebhookID]
+			if !ok {
+				webhook, err = s.GetWebhook(delivery.WebhookID)
+				if errors.Is(err, ErrWebhookNotFound) {
+					// deleted since, taking its deliveries with it
+					continue
+				}
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				webhooks[delivery.WebhookID] = webhook
+			}
+
+			if err := s.attempt(webhook, delivery); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) > 0 {
+			return errors.Join(errs...)
+		}
+
+		if len(deliveries) < deliveryBatchSize {
+			return nil
+		}
+	}
+}
+
+// attempt sends a delivery once and records the outcome.
+func (s *service) attempt(webhook *sqlc.Webhook, delivery sqlc.WebhookDelivery) error {
+	status, body, sendErr := s.send(webhook, delivery)
+
+	params := sqlc.RecordWebhookDeliveryAttemptParams{
+		ID:     delivery.ID,
+		Status: StatusSucceeded,
+		NextAttemptAt: pgtype.Timestamptz{
+			Time:  time.Now(),
+			Valid: true,
+		},
+		ResponseStatus: pgtype.Int4{
+			Int32: int32(status),
+			Valid: status != 0,
+		},
+		ResponseBody: pgtype.Text{
+			String: body,
+			Valid:  status != 0 && webhook.Scope == ScopeConference,
+		},
+	}
+	if sendErr == nil && (status < 200 || status > 299) {
+		sendErr = fmt.Errorf("endpoint responded with %d", status)
+	}
+	if sendErr != nil {
+		params.Error = pgtype.Text{
+			String: sendErr.Error(),
+			Valid:  true,
+		}
+		if int(delivery.Attempts) < len(retryDelays) {
+			params.Status = StatusPending
+			params.NextAttemptAt.Time = time.Now().Add(retryDelays[delivery.Attempts])
+		} else {
+			params.Status = StatusFailed
+		}
+	}
+
+	queries := sqlc.New(s.pool)
+
+	if err := queries.RecordWebhookDeliveryAttempt(context.Background(), params); err != nil {
+		return fmt.Errorf("could not record delivery attempt: %w", err)
+	}
+
+	return nil
+}
+
+// send posts the payload to the webhook's URL, returning the response
+// status and, for conference webhooks, the start of the response body.
+//
+// Requests are signed with the webhook's secret: X-Confplanner-Signature
+// is "sha256=" followed by the hex HMAC-SHA256 of the timestamp in
+// X-Confplanner-Timestamp, a full stop, and the request body.
+func (s *service) send(webhook *sqlc.Webhook, delivery sqlc.WebhookDelivery) (int, string, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, webhook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "confplanner-webhook")
+	req.Header.Set("X-Confplanner-Event", delivery.Event)
+	req.Header.Set("X-Confplanner-Delivery", strconv.Itoa(int(delivery.ID)))
+	req.Header.Set("X-Confplanner-Timestamp", timestamp)
+	req.Header.Set("X-Confplanner-Signature", "sha256="+sign(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	if webhook.Scope != ScopeConference {
+		return resp.StatusCode, "", nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, responseBodyLimit))
+	if err != nil {
+		return resp.StatusCode, "", nil
+	}
+
+	return resp.StatusCode, truncate(body), nil
+}
+
+var errForbiddenAddress = errors.New("webhook endpoints may not be private, loopback or link-local addresses")
+
+// newClient makes the client deliveries are sent with. Endpoints are
+// checked once resolved, so a hostname can't be pointed at an internal
+// address after the webhook is created, and redirects aren't followed.
+func newClient(allowInsecure bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			if allowInsecure {
+				return nil
+			}
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !isPublic(addrPort.Addr()) {
+				return errForbiddenAddress
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// sharedAddressSpace is used for carrier-grade NAT, and isn't reachable
+// from the internet.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+func sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// truncate turns a response body cut off at responseBodyLimit into text
+// postgres will store, dropping invalid and partial characters and NULs.
+func truncate(body []byte) string {
+	return strings.ReplaceAll(strings.ToValidUTF8(string(body), ""), "\x00", "")
+}
@@ -0,0 +1,398 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/LMBishop/confplanner/internal/random"
+	"github.com/LMBishop/confplanner/pkg/conference"
+	"github.com/LMBishop/confplanner/pkg/database/sqlc"
+	"github.com/LMBishop/confplanner/pkg/favourites"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Service interface {
+	CreateWebhook(userID int32, conferenceID int32, scope string, endpoint string, events []string) (*sqlc.Webhook, error)
+	GetWebhook(id int32) (*sqlc.Webhook, error)
+	GetConferenceWebhooks(conferenceID int32) ([]sqlc.Webhook, error)
+	GetUserWebhooks(userID int32) ([]sqlc.Webhook, error)
+	DeleteWebhook(id int32) error
+	GetDeliveries(webhookID int32) ([]sqlc.WebhookDelivery, error)
+	Redeliver(webhookID int32, deliveryID int32) (*sqlc.WebhookDelivery, error)
+	PublishScheduleChange(update conference.ScheduleUpdate) error
+	PublishFavouriteChange(change favourites.FavouriteChange) error
+}
+
+const (
+	ScopeConference = "conference"
+	ScopeUser       = "user"
+)
+
+const (
+	EventScheduleChanged  = "schedule.changed"
+	EventEventCancelled   = "event.cancelled"
+	EventFavouriteAdded   = "favourite.added"
+	EventFavouriteRemoved = "favourite.removed"
+)
+
+// Events lists the events each scope of webhook can subscribe to.
+var Events = map[string][]string{
+	ScopeConference: {EventScheduleChanged, EventEventCancelled},
+	ScopeUser:       {EventFavouriteAdded, EventFavouriteRemoved},
+}
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("delivery not found")
+	ErrBadURL           = errors.New("webhook URL must be an absolute https URL")
+	ErrBadEvent         = errors.New("unknown webhook event")
+)
+
+// deliveryLogSize is how many of the most recent deliveries are listed for
+// a webhook.
+const deliveryLogSize = 50
+
+type service struct {
+	pool *pgxpool.Pool
+	// allow plain HTTP endpoints and private addresses, for testing against
+	// a local receiver
+	allowInsecure bool
+	client        *http.Client
+	wake          chan struct{}
+}
+
+// NewService starts a worker which delivers queued payloads to webhook
+// endpoints, retrying failed deliveries with a backoff.
+func NewService(pool *pgxpool.Pool, allowInsecure bool) Service {
+	s := &service{
+		pool:          pool,
+		allowInsecure: allowInsecure,
+		client:        newClient(allowInsecure),
+		wake:          make(chan struct{}, 1),
+	}
+	go s.runDeliveries()
+
+	return s
+}
+
+func (s *service) CreateWebhook(userID int32, conferenceID int32, scope string, endpoint string, events []string) (*sqlc.Webhook, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && !(s.allowInsecure && u.Scheme == "http")) {
+		return nil, ErrBadURL
+	}
+
+	// no events means every event the scope offers
+	if len(events) == 0 {
+		events = Events[scope]
+	}
+	for _, event := range events {
+		if !slices.Contains(Events[scope], event) {
+			return nil, fmt.Errorf("%w: %s", ErrBadEvent, event)
+		}
+	}
+
+	secret, err := random.String(32)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := sqlc.New(s.pool)
+
+	webhook, err := queries.CreateWebhook(context.Background(), sqlc.CreateWebhookParams{
+		UserID:       userID,
+		ConferenceID: conferenceID,
+		Scope:        scope,
+		Url:          endpoint,
+		Secret:       secret,
+		Events:       slices.Compact(slices.Sorted(slices.Values(events))),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create webhook: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+func (s *service) GetWebhook(id int32) (*sqlc.Webhook, error) {
+	queries := sqlc.New(s.pool)
+
+	webhook, err := queries.GetWebhook(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("could not fetch webhook: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+func (s *service) GetConferenceWebhooks(conferenceID int32) ([]sqlc.Webhook, error) {
+	queries := sqlc.New(s.pool)
+
+	webhooks, err := queries.GetConferenceWebhooks(context.Background(), conferenceID)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (s *service) GetUserWebhooks(userID int32) ([]sqlc.Webhook, error) {
+	queries := sqlc.New(s.pool)
+
+	webhooks, err := queries.GetUserWebhooks(context.Background(), userID)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (s *service) DeleteWebhook(id int32) error {
+	queries := sqlc.New(s.pool)
+
+	rowsAffected, err := queries.DeleteWebhook(context.Background(), id)
+	if err != nil {
+		return fmt.Errorf("could not delete webhook: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (s *service) GetDeliveries(webhookID int32) ([]sqlc.WebhookDelivery, error) {
+	queries := sqlc.New(s.pool)
+
+	deliveries, err := queries.GetWebhookDeliveries(context.Background(), sqlc.GetWebhookDeliveriesParams{
+		WebhookID: webhookID,
+		Limit:     deliveryLogSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver queues the payload of an earlier delivery to be sent again as
+// a new delivery, leaving the original in the log as it was.
+func (s *service) Redeliver(webhookID int32, deliveryID int32) (*sqlc.WebhookDelivery, error) {
+	queries := sqlc.New(s.pool)
+
+	original, err := queries.GetWebhookDelivery(context.Background(), sqlc.GetWebhookDeliveryParams{
+		ID:        deliveryID,
+		WebhookID: webhookID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, fmt.Errorf("could not fetch delivery: %w", err)
+	}
+
+	delivery, err := queries.CreateWebhookDelivery(context.Background(), sqlc.CreateWebhookDeliveryParams{
+		WebhookID: webhookID,
+		Event:     original.Event,
+		Payload:   original.Payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not queue delivery: %w", err)
+	}
+	s.notify()
+
+	return &delivery, nil
+}
+
+// payload is the body of every webhook request.
+type payload struct {
+	Event        string    `json:"event"`
+	ConferenceID int32     `json:"conferenceID"`
+	Timestamp    time.Time `json:"timestamp"`
+	Data         any       `json:"data"`
+}
+
+type eventPayload struct {
+	ID    int32     `json:"id"`
+	GUID  string    `json:"guid,omitempty"`
+	Title string    `json:"title"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Room  string    `json:"room"`
+	URL   string    `json:"url,omitempty"`
+}
+
+type eventChangePayload struct {
+	Before eventPayload `json:"before"`
+	After  eventPayload `json:"after"`
+}
+
+type scheduleChangedPayload struct {
+	Added   []eventPayload       `json:"added"`
+	Changed []eventChangePayload `json:"changed"`
+	Removed []eventPayload       `json:"removed"`
+}
+
+type favouritePayload struct {
+	ID        int32      `json:"id"`
+	EventGUID string     `json:"eventGuid,omitempty"`
+	EventID   int32      `json:"eventId,omitempty"`
+	Title     string     `json:"title,omitempty"`
+	Start     *time.Time `json:"start,omitempty"`
+}
+
+func newEventPayload(event conference.Event) eventPayload {
+	return eventPayload{
+		ID:    event.ID,
+		GUID:  event.GUID,
+		Title: event.Title,
+		Start: event.Start,
+		End:   event.End,
+		Room:  event.Room,
+		URL:   event.URL,
+	}
+}
+
+func newFavouritePayload(favourite sqlc.Favourite) favouritePayload {
+	p := favouritePayload{
+		ID:      favourite.ID,
+		EventID: favourite.EventID.Int32,
+		Title:   favourite.EventTitle.String,
+	}
+	if favourite.EventGuid.Valid {
+		p.EventGUID = favourite.EventGuid.String()
+	}
+	if favourite.EventStart.Valid {
+		p.Start = &favourite.EventStart.Time
+	}
+	return p
+}
+
+// PublishScheduleChange queues schedule.changed and event.cancelled
+// payloads for the conference's webhooks.
+func (s *service) PublishScheduleChange(update conference.ScheduleUpdate) error {
+	if update.Changes == nil || update.Changes.Empty() {
+		return nil
+	}
+	conferenceID, diff := update.ConferenceID, *update.Changes
+
+	webhooks, err := s.GetConferenceWebhooks(conferenceID)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	changed := scheduleChangedPayload{
+		Added:   make([]eventPayload, 0, len(diff.Added)),
+		Changed: make([]eventChangePayload, 0, len(diff.Changed)),
+		Removed: make([]eventPayload, 0, len(diff.Removed)),
+	}
+	for _, event := range diff.Added {
+		changed.Added = append(changed.Added, newEventPayload(event))
+	}
+	for _, c := range diff.Changed {
+		changed.Changed = append(changed.Changed, eventChangePayload{
+			Before: newEventPayload(c.Before),
+			After:  newEventPayload(c.After),
+		})
+	}
+	for _, event := range diff.Removed {
+		changed.Removed = append(changed.Removed, newEventPayload(event))
+	}
+
+	errs := []error{s.enqueue(webhooks, conferenceID, EventScheduleChanged, changed)}
+	for _, event := range changed.Removed {
+		errs = append(errs, s.enqueue(webhooks, conferenceID, EventEventCancelled, event))
+	}
+
+	return errors.Join(errs...)
+}
+
+// PublishFavouriteChange queues favourite.added and favourite.removed
+// payloads for the user's own webhooks on the conference.
+func (s *service) PublishFavouriteChange(change favourites.FavouriteChange) error {
+	if len(change.Created) == 0 && len(change.Deleted) == 0 {
+		return nil
+	}
+
+	all, err := s.GetUserWebhooks(change.UserID)
+	if err != nil {
+		return err
+	}
+	var webhooks []sqlc.Webhook
+	for _, webhook := range all {
+		if webhook.ConferenceID == change.ConferenceID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, favourite := range change.Created {
+		errs = append(errs, s.enqueue(webhooks, change.ConferenceID, EventFavouriteAdded, newFavouritePayload(favourite)))
+	}
+	for _, favourite := range change.Deleted {
+		errs = append(errs, s.enqueue(webhooks, change.ConferenceID, EventFavouriteRemoved, newFavouritePayload(favourite)))
+	}
+
+	return errors.Join(errs...)
+}
+
+// enqueue queues a delivery of the event to each webhook subscribed to it.
+func (s *service) enqueue(webhooks []sqlc.Webhook, conferenceID int32, event string, data any) error {
+	body, err := json.Marshal(payload{
+		Event:        event,
+		ConferenceID: conferenceID,
+		Timestamp:    time.Now().UTC(),
+		Data:         data,
+	})
+	if err != nil {
+		return err
+	}
+
+	queries := sqlc.New(s.pool)
+
+	var errs []error
+	queued := false
+	for _, webhook := range webhooks {
+		if !slices.Contains(webhook.Events, event) {
+			continue
+		}
+		_, err := queries.CreateWebhookDelivery(context.Background(), sqlc.CreateWebhookDeliveryParams{
+			WebhookID: webhook.ID,
+			Event:     event,
+			Payload:   body,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not queue delivery: %w", err))
+			continue
+		}
+		queued = true
+	}
+	if queued {
+		s.notify()
+	}
+
+	return errors.Join(errs...)
+}
+
+// notify wakes the delivery worker without waiting for its next tick.
+func (s *service) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,80 @@
+import { useConferenceStore } from "~/stores/conference";
+import { expireAuth } from "./expire-auth";
+
+type StreamHandlers = Record<string, (data: any) => void>
+
+// EventSource can't send the Authorization header, so the stream is read
+// with fetch instead and reconnected by hand, resuming from the last message
+export default function(handlers: StreamHandlers) {
+  const conferenceStore = useConferenceStore()
+  const authStore = useAuthStore()
+  const config = useRuntimeConfig();
+
+  let controller: AbortController | null = null
+  let lastEventId = ''
+  let retry: ReturnType<typeof setTimeout> | undefined
+
+  async function connect() {
+    controller = new AbortController()
+    try {
+      const response = await fetch(config.public.baseURL + '/conference/' + conferenceStore.id + '/stream', {
+        headers: {
+          Authorization: `Bearer ${authStore.token}`,
+          ...(lastEventId ? { 'Last-Event-ID': lastEventId } : {}),
+        },
+        signal: controller.signal,
+      })
+      if (response.status === 401) {
+        expireAuth()
+        return
+      }
+      if (!response.ok || !response.body) {
+        throw new Error('stream unavailable')
+      }
+
+      const reader = response.body.pipeThrough(new TextDecoderStream()).getReader()
+      let buffer = ''
+      while (true) {
+        const { value, done } = await reader.read()
+        if (done) {
+          break
+        }
+        buffer += value
+        let end
+        while ((end = buffer.indexOf('\n\n')) !== -1) {
+          dispatch(buffer.slice(0, end))
+          buffer = buffer.slice(end + 2)
+        }
+      }
+    } catch {
+      if (controller?.signal.aborted) {
+        return
+      }
+    }
+    retry = setTimeout(connect, 5000)
+  }
+
+  function dispatch(block: string) {
+    let event = 'message'
+    let data = ''
+    for (const line of block.split('\n')) {
+      if (line.startsWith('id: ')) {
+        lastEventId = line.slice(4)
+      } else if (line.startsWith('event: ')) {
+        event = line.slice(7)
+      } else if (line.startsWith('data: ')) {
+        data += line.slice(6)
+      }
+    }
+    if (data && handlers[event]) {
+      handlers[event](JSON.parse(data))
+    }
+  }
+
+  function close() {
+    clearTimeout(retry)
+    controller?.abort()
+  }
+
+  return { connect, close }
+}
@@ -0,0 +1,47 @@
+// Subscribes this browser to push reminders for agenda items, using the
+// service worker registered by the PWA module
+export default function() {
+  const config = useRuntimeConfig();
+
+  const supported = import.meta.client && 'serviceWorker' in navigator && 'PushManager' in window
+  const subscribed = ref(false)
+
+  async function registration() {
+    return navigator.serviceWorker.ready
+  }
+
+  async function refresh() {
+    if (!supported) {
+      return
+    }
+    const subscription = await (await registration()).pushManager.getSubscription()
+    subscribed.value = subscription !== null
+  }
+
+  async function subscribe() {
+    const key = await $api<any>(config.public.baseURL + '/push/key')
+    const subscription = await (await registration()).pushManager.subscribe({
+      userVisibleOnly: true,
+      applicationServerKey: key.data.publicKey,
+    })
+    await $api(config.public.baseURL + '/push/subscriptions', {
+      method: 'POST',
+      body: subscription.toJSON(),
+    })
+    subscribed.value = true
+  }
+
+  async function unsubscribe() {
+    const subscription = await (await registration()).pushManager.getSubscription()
+    if (subscription) {
+      await $api(config.public.baseURL + '/push/subscriptions', {
+        method: 'DELETE',
+        body: { endpoint: subscription.endpoint },
+      }).catch(() => {})
+      await subscription.unsubscribe()
+    }
+    subscribed.value = false
+  }
+
+  return { supported, subscribed, refresh, subscribe, unsubscribe }
+}
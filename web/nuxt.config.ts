@@ -40,4 +40,10 @@ export default defineNuxtConfig({
   },
   
   modules: ["@pinia/nuxt", "@vite-pwa/nuxt"],
+
+  pwa: {
+    workbox: {
+      importScripts: ["/push-sw.js"],
+    },
+  },
 });
@@ -0,0 +1,26 @@
+// Imported into the generated service worker to show push reminders
+self.addEventListener('push', (event) => {
+  if (!event.data) {
+    return
+  }
+  const message = event.data.json()
+  event.waitUntil(self.registration.showNotification(message.title, {
+    body: message.body,
+    tag: message.tag,
+    data: { url: message.url },
+  }))
+})
+
+self.addEventListener('notificationclick', (event) => {
+  event.notification.close()
+  const url = event.notification.data?.url || '/'
+  event.waitUntil(self.clients.matchAll({ type: 'window' }).then((clients) => {
+    for (const client of clients) {
+      if ('focus' in client) {
+        client.navigate(url)
+        return client.focus()
+      }
+    }
+    return self.clients.openWindow(url)
+  }))
+})
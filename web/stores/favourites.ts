@@ -5,6 +5,11 @@ interface Favourite {
   id: number;
   eventGuid?: string;
   eventId?: number;
+  note?: string;
+  priority?: '' | 'must-see' | 'maybe';
+  attendance?: '' | 'attended' | 'skipped';
+  eventTitle?: string;
+  orphaned?: boolean;
 }
 
 export const useFavouritesStore = defineStore('favourites', () => {
@@ -54,11 +54,39 @@ export interface Event {
   persons: Person[];
   attachments: Attachment[];
   links: Link[];
+  amendment?: Amendment;
+  custom?: Custom;
+  // label of the feed the event came from, for conferences with several
+  source?: string;
+}
+
+// set on events authored locally rather than fetched from the source
+interface Custom {
+  shared: boolean;
+  ownerId?: number;
+}
+
+// set on events an admin has overridden locally
+interface Amendment {
+  fields: ('start' | 'end' | 'room' | 'title')[];
+  cancelled: boolean;
+  note?: string;
+  original: {
+    start: Date;
+    end: Date;
+    room: string;
+    title: string;
+  };
+  updatedAt: Date;
 }
 
 interface Person {
   id: number;
+  guid?: string;
   name: string;
+  biography?: string;
+  avatar?: string;
+  links?: Link[];
 }
 
 interface Attachment {
@@ -157,6 +185,10 @@ export const useScheduleStore = defineStore('schedule', () => {
 function normalizeDates(event: Event, timeZone: string) {
   event.start = new TZDate(event.start, timeZone)
   event.end = new TZDate(event.end, timeZone)
+  if (event.amendment) {
+    event.amendment.original.start = new TZDate(event.amendment.original.start, timeZone)
+    event.amendment.original.end = new TZDate(event.amendment.original.end, timeZone)
+  }
 }
 
 function parseDuration(duration: string) {